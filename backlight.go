@@ -1,21 +1,35 @@
 package charLCDRGBI2C
 
-import (
-	"log"
-
-	"github.com/googolgl/go-mcp23017"
-)
-
 // Backlight
 func (lcd *CharLCDRGBI2C) SetBacklight(on bool) error {
-	if on {
+	lcd.lock()
+	defer lcd.unlock()
+	return lcd.setBacklight(on)
+}
+
+// setBacklight turns the backlight on or off
+func (lcd *CharLCDRGBI2C) setBacklight(on bool) error {
+	var err error
+	switch {
+	case lcd.backlightPin == "":
+		// Not wired
+	case on:
 		// Set as output to turn backlight ON
-		lcd.mcp.Set(mcp23017.Pins{BacklightPin}).OUTPUT()
-		log.Println("Backlight ON")
-	} else {
+		err = lcd.pins.Output(lcd.backlightPin)
+	default:
 		// Set as input to turn backlight OFF
-		lcd.mcp.Set(mcp23017.Pins{BacklightPin}).INPUT()
-		log.Println("Backlight OFF")
+		err = lcd.pins.Input(lcd.backlightPin)
 	}
+	if lcd.track(err) != nil {
+		return err
+	}
+	lcd.backlight = on
 	return nil
 }
+
+// Backlight reports whether the backlight is on
+func (lcd *CharLCDRGBI2C) Backlight() bool {
+	lcd.lock()
+	defer lcd.unlock()
+	return lcd.backlight
+}
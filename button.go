@@ -1,15 +1,58 @@
 package charLCDRGBI2C
 
 import (
+	"context"
 	"log"
-
-	"github.com/googolgl/go-mcp23017"
+	"slices"
+	"time"
 )
 
+// Buttons lists the pins of the on-board buttons
+var Buttons = []string{LeftButton, UpButton, DownButton, RightButton, SelectButton}
+
+// buttonNames are the names tools and protocols use for the buttons
+var buttonNames = map[string]string{
+	LeftButton:   "left",
+	UpButton:     "up",
+	DownButton:   "down",
+	RightButton:  "right",
+	SelectButton: "select",
+}
+
+// ButtonName returns the lower case name of a button pin, e.g. "select"
+func ButtonName(button string) string {
+	return buttonNames[button]
+}
+
+// ButtonByName returns the pin of a button name
+func ButtonByName(name string) (string, bool) {
+	for button, buttonName := range buttonNames {
+		if buttonName == name {
+			return button, true
+		}
+	}
+	return "", false
+}
+
+// ButtonEvent reports a button being pressed or released
+type ButtonEvent struct {
+	Button  string    // Button pin, e.g. LeftButton
+	Pressed bool      // True when pressed, false when released
+	Time    time.Time // When the change was seen
+}
+
 // IsButtonPressed checks if a specific button is pressed
 func (lcd *CharLCDRGBI2C) IsButtonPressed(buttonPin string) bool {
+	if !slices.Contains(lcd.buttons, buttonPin) {
+		return false
+	}
+
+	lcd.lock()
+	defer lcd.unlock()
+
 	// Read the button state (LOW when pressed because of pull-up resistor)
-	pinStates, err := lcd.mcp.Get(mcp23017.Pins{buttonPin})
+	pinStates, err := lcd.pins.Read(buttonPin)
+	lcd.trackRead(err)
 	if err != nil {
 		log.Printf("Error reading button state: %v", err)
 		return false
@@ -46,3 +89,60 @@ func (lcd *CharLCDRGBI2C) RightButton() bool {
 func (lcd *CharLCDRGBI2C) SelectButton() bool {
 	return lcd.IsButtonPressed(SelectButton)
 }
+
+// WatchButtons polls the buttons every interval and sends an event for
+// every press and release. The polling interval also debounces the buttons,
+// 20ms works well. The channel is closed when ctx is done or the LCD is
+// closed.
+func (lcd *CharLCDRGBI2C) WatchButtons(ctx context.Context, interval time.Duration) <-chan ButtonEvent {
+	events := make(chan ButtonEvent, len(Buttons))
+	buttons := lcd.buttons
+
+	started := lcd.background(func(done <-chan struct{}) {
+		defer close(events)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		pressed := make(map[string]bool)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-done:
+				return
+			case now := <-ticker.C:
+				lcd.lock()
+				pinStates, err := lcd.pins.Read(buttons...)
+				lcd.trackRead(err)
+				lcd.unlock()
+				if err != nil {
+					log.Printf("Error reading button state: %v", err)
+					continue
+				}
+
+				for _, button := range buttons {
+					// LOW when pressed because of pull-up resistor
+					isPressed := pinStates[button] == 0
+					if isPressed == pressed[button] {
+						continue
+					}
+					pressed[button] = isPressed
+
+					select {
+					case events <- ButtonEvent{Button: button, Pressed: isPressed, Time: now}:
+					case <-ctx.Done():
+						return
+					case <-done:
+						return
+					}
+				}
+			}
+		}
+	})
+	if !started {
+		close(events)
+	}
+
+	return events
+}
@@ -1,10 +1,13 @@
 package charLCDRGBI2C
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
 	"time"
 
 	"github.com/googolgl/go-i2c"
-	"github.com/googolgl/go-mcp23017"
 )
 
 const (
@@ -61,9 +64,11 @@ const (
 	LCD_MOVELEFT    = 0x00
 
 	// Function set flags
+	LCD_8BITMODE = 0x10
 	LCD_4BITMODE = 0x00
 	LCD_2LINE    = 0x08
 	LCD_1LINE    = 0x00
+	LCD_5X10DOTS = 0x04
 	LCD_5X8DOTS  = 0x00
 
 	// Direction constants
@@ -72,16 +77,42 @@ const (
 )
 
 // Row offset addresses for different LCD lines
+//
+// Deprecated: these offsets are only correct for 20x4 panels, use the
+// RowOffsets of a Geometry instead.
 var LCD_ROW_OFFSETS = []byte{0x00, 0x40, 0x14, 0x54}
 
 // CharLCDRGBI2C represents a character LCD with an RGB LED controlled via I2C.
+//
+// It is safe for concurrent use, every method runs as a single operation on
+// the bus so that commands from different goroutines never interleave.
 type CharLCDRGBI2C struct {
-	mcp        *mcp23017.MCP23017 // I2C expander
-	columns    int                // Number of columns on the LCD
-	lines      int                // Number of lines on the LCD
-	backlight  bool               // Backlight status
-	rgb        [3]string          // RGB pins
-	colorValue [3]int             // RGB color values (0-100)
+	mu sync.Mutex // Serializes operations on the bus and driver state
+
+	// Background goroutines
+	done      chan struct{}  // Closed by Close to stop background goroutines
+	wg        sync.WaitGroup // Running background goroutines
+	closeOnce sync.Once      // Guards closing done
+	closeErr  error          // Result of Close
+
+	pins         PinDriver // I2C expander pins
+	columns      int       // Number of columns on the LCD
+	lines        int       // Number of lines on the LCD
+	rowOffsets   []byte    // DDRAM start address of each line
+	backlight    bool      // Backlight status
+	rgb          [3]string // RGB pins, empty when not wired
+	backlightPin string    // Backlight pin, empty when not wired
+	buttons      []string  // Wired button pins
+	colorValue   [3]int    // RGB color values (0-100)
+
+	// Controllers
+	enablePins      []string // Enable pin of each controller
+	controllerLines int      // Number of lines driven by each controller
+	controller      int      // Controller the cursor is on
+
+	// Bus and font
+	dataPins []string // Data pins, D4-D7 in 4-bit mode or D0-D7 in 8-bit mode
+	font5x10 bool     // 5x10 dot font instead of 5x8
 
 	// Display control
 	displayControl  byte   // Control byte for display settings
@@ -92,77 +123,198 @@ type CharLCDRGBI2C struct {
 	columnAlign     bool   // Column alignment setting
 	message         string // Message to be displayed
 	direction       int    // LEFT_TO_RIGHT or RIGHT_TO_LEFT
+
+	// Display contents
+	stateFile string      // File the state is saved to, see WithStateFile
+	saveTimer *time.Timer // Pending save of the state file
+	saved     []byte      // State file contents last written
+	dirty     bool        // The running operation wrote to the bus
+	fb        framebuffer // Characters shown on the display
+	glyphs    [8][]byte   // Custom character patterns, nil when not defined
+	term      terminal    // io.Writer state
+
+	recovery recovery // Bus error handling, see WithRecovery
+}
+
+// New creates an LCD with one of the known geometry profiles, see LookupGeometry
+func New(i2c *i2c.Options, columns, lines int, opts ...Option) (*CharLCDRGBI2C, error) {
+	geometry, err := LookupGeometry(columns, lines)
+	if err != nil {
+		return nil, err
+	}
+	return NewWithGeometry(i2c, geometry, opts...)
 }
 
-func New(i2c *i2c.Options, columns, lines int) (*CharLCDRGBI2C, error) {
+// NewWithGeometry creates an LCD with a custom geometry
+func NewWithGeometry(i2c *i2c.Options, geometry Geometry, opts ...Option) (*CharLCDRGBI2C, error) {
+	if err := geometry.Validate(); err != nil {
+		return nil, err
+	}
+
 	// Initialize MCP23017
-	mcp, err := mcp23017.New(i2c)
+	driver, err := NewMCP23017Driver(i2c)
 	if err != nil {
 		return nil, err
 	}
+	return NewWithDriver(driver, geometry, opts...)
+}
+
+// NewWithDriver creates an LCD driven through any PinDriver
+func NewWithDriver(driver PinDriver, geometry Geometry, opts ...Option) (*CharLCDRGBI2C, error) {
+	if err := geometry.Validate(); err != nil {
+		return nil, err
+	}
 
 	lcd := &CharLCDRGBI2C{
-		mcp:        mcp,
-		columns:    columns,
-		lines:      lines,
-		backlight:  true,
-		rgb:        [3]string{RedPin, GreenPin, BluePin},
-		colorValue: [3]int{0, 0, 0},
+		pins:            driver,
+		columns:         geometry.Columns,
+		lines:           geometry.Lines,
+		rowOffsets:      append([]byte(nil), geometry.RowOffsets...),
+		backlight:       true,
+		rgb:             [3]string{RedPin, GreenPin, BluePin},
+		backlightPin:    BacklightPin,
+		buttons:         Buttons,
+		colorValue:      [3]int{0, 0, 0},
+		enablePins:      []string{LcdEnablePin},
+		controllerLines: geometry.controllerLines(),
+		dataPins:        []string{LcdD4Pin, LcdD5Pin, LcdD6Pin, LcdD7Pin},
+		done:            make(chan struct{}),
+		recovery: recovery{
+			retries:    defaultRetries,
+			backoff:    defaultBackoff,
+			maxBackoff: defaultMaxBackoff,
+		},
+	}
+	for _, opt := range opts {
+		opt(lcd)
+	}
+	if len(lcd.enablePins) != geometry.controllers() {
+		return nil, fmt.Errorf("LCD geometry %dx%d needs %d enable pins, got %d",
+			geometry.Columns, geometry.Lines, geometry.controllers(), len(lcd.enablePins))
 	}
+	if lcd.font5x10 && lcd.controllerLines > 1 {
+		return nil, fmt.Errorf("5x10 font is only available on 1-line displays")
+	}
+	if err := lcd.checkPins(); err != nil {
+		return nil, err
+	}
+
+	lcd.fb = newFramebuffer(lcd.columns, lcd.lines)
+
+	// State saved by a previous process, see WithStateFile
+	saved, err := lcd.loadSavedState()
+	if err != nil {
+		return nil, err
+	}
+	// A board whose LCD pins are still outputs has kept its contents, unless
+	// it was changed after the state was saved
+	keep := saved != nil && lcd.configured() && lcd.matchesBoard(*saved)
 
 	lcd.setupPins()
 
-	lcd.initialize()
+	if keep {
+		if err := lcd.adopt(*saved); err != nil {
+			return nil, err
+		}
+	} else {
+		lcd.initialize()
+		if saved != nil {
+			if err := lcd.restore(*saved); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Give a flaky bus the same chances as later operations get
+	lcd.mu.Lock()
+	lcd.recoverBus()
+	health := lcd.recovery.health
+	lcd.mu.Unlock()
+	if !health.OK {
+		return nil, fmt.Errorf("LCD does not respond: %w", health.LastError)
+	}
 
 	return lcd, nil
 }
 
+// checkPins makes sure every pin is an MCP23017 pin and no two features
+// share one, e.g. an 8-bit data bus on port A needs the buttons and LED
+// moved off it, see WithButtons and WithLEDPins
+func (lcd *CharLCDRGBI2C) checkPins() error {
+	uses := make(map[string]string)
+	use := func(feature string, pins ...string) error {
+		for _, pin := range pins {
+			if len(pin) != 2 || (pin[0] != 'A' && pin[0] != 'B') || pin[1] < '0' || pin[1] > '7' {
+				return fmt.Errorf("invalid %s pin %q", feature, pin)
+			}
+			if other, ok := uses[pin]; ok {
+				return fmt.Errorf("%s pin %s is already used by %s", feature, pin, other)
+			}
+			uses[pin] = feature
+		}
+		return nil
+	}
+
+	for _, button := range lcd.buttons {
+		if ButtonName(button) == "" {
+			return fmt.Errorf("%q is not one of the board's buttons", button)
+		}
+	}
+	return errors.Join(
+		use("RS", LcdRsPin),
+		use("RW", RwPin),
+		use("enable", lcd.enablePins...),
+		use("data", lcd.dataPins...),
+		use("LED", lcd.ledPins()...),
+		use("backlight", lcd.wired(lcd.backlightPin)...),
+		use("button", lcd.buttons...),
+	)
+}
+
+// ledPins returns the wired RGB LED pins
+func (lcd *CharLCDRGBI2C) ledPins() []string {
+	return lcd.wired(lcd.rgb[:]...)
+}
+
+// wired returns the pins that are not empty
+func (lcd *CharLCDRGBI2C) wired(pins ...string) []string {
+	var wired []string
+	for _, pin := range pins {
+		if pin != "" {
+			wired = append(wired, pin)
+		}
+	}
+	return wired
+}
+
 func (lcd *CharLCDRGBI2C) setupPins() {
 	// Set LCD control pins as outputs
-	lcd.mcp.Set(mcp23017.Pins{LcdRsPin, LcdEnablePin, LcdD4Pin, LcdD5Pin, LcdD6Pin, LcdD7Pin}).OUTPUT()
-	lcd.mcp.Set(mcp23017.Pins{RwPin}).OUTPUT()
+	lcd.track(lcd.pins.Output(LcdRsPin))
+	lcd.track(lcd.pins.Output(lcd.dataPins...))
+	lcd.track(lcd.pins.Output(lcd.enablePins...))
+	lcd.track(lcd.pins.Output(RwPin))
 
 	// Set RGB LED pins as outputs
-	lcd.mcp.Set(mcp23017.Pins{RedPin, GreenPin, BluePin}).OUTPUT()
+	if pins := lcd.ledPins(); len(pins) > 0 {
+		lcd.track(lcd.pins.Output(pins...))
+	}
 
 	// Set Button pins as inputs with pull-up
-	lcd.mcp.Set(mcp23017.Pins{LeftButton, UpButton, DownButton, RightButton, SelectButton}).INPUT()
-	lcd.mcp.Set(mcp23017.Pins{LeftButton, UpButton, DownButton, RightButton, SelectButton}).PULLUP()
+	if len(lcd.buttons) > 0 {
+		lcd.track(lcd.pins.Input(lcd.buttons...))
+		lcd.track(lcd.pins.PullUp(lcd.buttons...))
+	}
 }
 
 func (lcd *CharLCDRGBI2C) initialize() {
-	// Wait for LCD to be ready
-	time.Sleep(50 * time.Millisecond)
-
-	// Pull RS low to begin commands
-	lcd.mcp.Set(mcp23017.Pins{LcdRsPin}).LOW()
-	lcd.mcp.Set(mcp23017.Pins{LcdEnablePin}).LOW()
-	lcd.mcp.Set(mcp23017.Pins{RwPin}).LOW() // Write mode
-
-	// 4-bit mode initialization sequence
-	lcd.write4bits(0x03)
-	time.Sleep(5 * time.Millisecond)
-	lcd.write4bits(0x03)
-	time.Sleep(5 * time.Millisecond)
-	lcd.write4bits(0x03)
-	time.Sleep(1 * time.Millisecond)
-	lcd.write4bits(0x02) // Set to 4-bit mode
-	time.Sleep(1 * time.Millisecond)
-
-	// Initialize display control
+	// Initialize display control and entry mode
 	lcd.displayControl = LCD_DISPLAYON | LCD_CURSOROFF | LCD_BLINKOFF
-	lcd.displayFunction = LCD_4BITMODE | LCD_1LINE | LCD_2LINE | LCD_5X8DOTS
 	lcd.displayMode = LCD_ENTRYLEFT | LCD_ENTRYSHIFTDECREMENT
 
-	// Write to displaycontrol
-	lcd.write8(LCD_DISPLAYCONTROL | lcd.displayControl)
-	// Write to displayfunction
-	lcd.write8(LCD_FUNCTIONSET | lcd.displayFunction)
-	// Set entry mode
-	lcd.write8(LCD_ENTRYMODESET | lcd.displayMode)
+	lcd.resync()
 
 	// Clear display
-	lcd.Clear()
+	lcd.clear()
 
 	// Initialize tracking variables
 	lcd.row = 0
@@ -172,33 +324,122 @@ func (lcd *CharLCDRGBI2C) initialize() {
 	lcd.message = ""
 
 	// Turn off all RGB LEDs initially
-	lcd.SetColor(0, 0, 0)
+	lcd.setColor(0, 0, 0)
+	lcd.setBacklight(lcd.backlight)
+}
+
+// resync runs the initialization sequence that puts every controller in
+// the bus mode whatever state it is in, then writes the function, display
+// control and entry mode settings. DDRAM and CGRAM are left as they are.
+func (lcd *CharLCDRGBI2C) resync() {
+	// Wait for LCD to be ready
+	time.Sleep(50 * time.Millisecond)
+
+	// Pull RS low to begin commands
+	lcd.track(lcd.pins.Low(LcdRsPin))
+	lcd.track(lcd.pins.Low(lcd.enablePins...))
+	lcd.track(lcd.pins.Low(RwPin)) // Write mode
+
+	// Initialization sequence, on every controller
+	for controller := range lcd.enablePins {
+		lcd.controller = controller
+		if lcd.eightBit() {
+			lcd.writeBus(0x30)
+			time.Sleep(5 * time.Millisecond)
+			lcd.writeBus(0x30)
+			time.Sleep(5 * time.Millisecond)
+			lcd.writeBus(0x30)
+			time.Sleep(1 * time.Millisecond)
+		} else {
+			lcd.write4bits(0x03)
+			time.Sleep(5 * time.Millisecond)
+			lcd.write4bits(0x03)
+			time.Sleep(5 * time.Millisecond)
+			lcd.write4bits(0x03)
+			time.Sleep(1 * time.Millisecond)
+			lcd.write4bits(0x02) // Set to 4-bit mode
+			time.Sleep(1 * time.Millisecond)
+		}
+	}
+	lcd.controller = 0
+
+	// Function set for the bus, lines and font
+	lcd.displayFunction = LCD_4BITMODE | LCD_1LINE | LCD_5X8DOTS
+	if lcd.eightBit() {
+		lcd.displayFunction |= LCD_8BITMODE
+	}
+	if lcd.controllerLines > 1 {
+		lcd.displayFunction |= LCD_2LINE
+	}
+	if lcd.font5x10 {
+		lcd.displayFunction |= LCD_5X10DOTS
+	}
+
+	// Write to displaycontrol
+	lcd.updateDisplayControl()
+	// Write to displayfunction
+	lcd.command(LCD_FUNCTIONSET | lcd.displayFunction)
+	// Set entry mode
+	lcd.command(LCD_ENTRYMODESET | lcd.displayMode)
 }
 
 // Clear clears the LCD display
 func (lcd *CharLCDRGBI2C) Clear() {
-	lcd.write8(LCD_CLEARDISPLAY)
+	lcd.lock()
+	defer lcd.unlock()
+	lcd.clear()
+}
+
+// clear clears the LCD display, waiting for the command to finish
+func (lcd *CharLCDRGBI2C) clear() {
+	lcd.command(LCD_CLEARDISPLAY)
+	lcd.fb.clear()
 	time.Sleep(3 * time.Millisecond) // This command takes a long time
+
+	// Clearing also sets the entry mode to increment
+	lcd.command(LCD_ENTRYMODESET | lcd.displayMode)
 }
 
 // Home moves cursor to home position
 func (lcd *CharLCDRGBI2C) Home() {
-	lcd.write8(LCD_RETURNHOME)
+	lcd.lock()
+	defer lcd.unlock()
+	lcd.command(LCD_RETURNHOME)
+	lcd.fb.moveTo(0, 0)
 	time.Sleep(3 * time.Millisecond) // This command takes a long time
 }
 
 // CursorPosition sets the cursor position
 func (lcd *CharLCDRGBI2C) CursorPosition(column, row int) {
-	// Clamp row to the last row of the display
+	lcd.lock()
+	defer lcd.unlock()
+	lcd.cursorPosition(column, row)
+}
+
+// cursorPosition sets the cursor position
+func (lcd *CharLCDRGBI2C) cursorPosition(column, row int) {
+	// Clamp row to the rows of the display
 	if row >= lcd.lines {
 		row = lcd.lines - 1
 	}
-	// Clamp to last column of display
+	if row < 0 {
+		row = 0
+	}
+	// Clamp to the columns of the display
 	if column >= lcd.columns {
 		column = lcd.columns - 1
 	}
+	if column < 0 {
+		column = 0
+	}
+	// Move the cursor to the controller driving the row
+	if controller := row / lcd.controllerLines; controller != lcd.controller {
+		lcd.controller = controller
+		lcd.updateDisplayControl()
+	}
 	// Set location
-	lcd.write8(LCD_SETDDRAMADDR | (byte(column) + LCD_ROW_OFFSETS[row]))
+	lcd.write8(LCD_SETDDRAMADDR | (byte(column) + lcd.rowOffsets[row]))
+	lcd.fb.moveTo(column, row)
 	// Update row and column tracking
 	lcd.row = row
 	lcd.column = column
@@ -206,46 +447,58 @@ func (lcd *CharLCDRGBI2C) CursorPosition(column, row int) {
 
 // SetCursor enables or disables the cursor
 func (lcd *CharLCDRGBI2C) SetCursor(show bool) {
+	lcd.lock()
+	defer lcd.unlock()
 	if show {
 		lcd.displayControl |= LCD_CURSORON
 	} else {
 		lcd.displayControl &= ^byte(LCD_CURSORON) // Use explicit type conversion
 	}
-	lcd.write8(LCD_DISPLAYCONTROL | lcd.displayControl)
+	lcd.updateDisplayControl()
 }
 
 // SetBlink enables or disables cursor blinking
 func (lcd *CharLCDRGBI2C) SetBlink(blink bool) {
+	lcd.lock()
+	defer lcd.unlock()
 	if blink {
 		lcd.displayControl |= LCD_BLINKON
 	} else {
 		lcd.displayControl &= ^byte(LCD_BLINKON) // Use explicit type conversion
 	}
-	lcd.write8(LCD_DISPLAYCONTROL | lcd.displayControl)
+	lcd.updateDisplayControl()
 }
 
 // SetDisplay enables or disables the entire display
 func (lcd *CharLCDRGBI2C) SetDisplay(enable bool) {
+	lcd.lock()
+	defer lcd.unlock()
 	if enable {
 		lcd.displayControl |= LCD_DISPLAYON
 	} else {
 		lcd.displayControl &= ^byte(LCD_DISPLAYON) // Use explicit type conversion
 	}
-	lcd.write8(LCD_DISPLAYCONTROL | lcd.displayControl)
+	lcd.updateDisplayControl()
 }
 
 // MoveLeft moves displayed text left one column
 func (lcd *CharLCDRGBI2C) MoveLeft() {
-	lcd.write8(LCD_CURSORSHIFT | LCD_DISPLAYMOVE | LCD_MOVELEFT)
+	lcd.lock()
+	defer lcd.unlock()
+	lcd.command(LCD_CURSORSHIFT | LCD_DISPLAYMOVE | LCD_MOVELEFT)
 }
 
 // MoveRight moves displayed text right one column
 func (lcd *CharLCDRGBI2C) MoveRight() {
-	lcd.write8(LCD_CURSORSHIFT | LCD_DISPLAYMOVE | LCD_MOVERIGHT)
+	lcd.lock()
+	defer lcd.unlock()
+	lcd.command(LCD_CURSORSHIFT | LCD_DISPLAYMOVE | LCD_MOVERIGHT)
 }
 
 // SetTextDirection sets the text direction
 func (lcd *CharLCDRGBI2C) SetTextDirection(direction int) {
+	lcd.lock()
+	defer lcd.unlock()
 	lcd.direction = direction
 	if direction == LEFT_TO_RIGHT {
 		lcd.leftToRight()
@@ -257,84 +510,184 @@ func (lcd *CharLCDRGBI2C) SetTextDirection(direction int) {
 // leftToRight sets text direction from left to right
 func (lcd *CharLCDRGBI2C) leftToRight() {
 	lcd.displayMode |= LCD_ENTRYLEFT
-	lcd.write8(LCD_ENTRYMODESET | lcd.displayMode)
+	lcd.command(LCD_ENTRYMODESET | lcd.displayMode)
 }
 
 // rightToLeft sets text direction from right to left
 func (lcd *CharLCDRGBI2C) rightToLeft() {
 	lcd.displayMode &= ^byte(LCD_ENTRYLEFT) // Use explicit type conversion
-	lcd.write8(LCD_ENTRYMODESET | lcd.displayMode)
+	lcd.command(LCD_ENTRYMODESET | lcd.displayMode)
 }
 
 // SetColumnAlign sets column alignment for newlines
 func (lcd *CharLCDRGBI2C) SetColumnAlign(enable bool) {
+	lcd.lock()
+	defer lcd.unlock()
 	lcd.columnAlign = enable
 }
 
 // CreateChar creates a custom character
+//
+// Patterns have 8 rows with the 5x8 font, or 11 rows with the 5x10 font.
+// Missing rows are left blank. The 5x10 font ignores bit 0 of the
+// character code, so locations 2n and 2n+1 share one pattern.
 func (lcd *CharLCDRGBI2C) CreateChar(location byte, pattern []byte) {
-	// Only positions 0-7 are allowed
+	lcd.lock()
+	defer lcd.unlock()
+	lcd.createChar(location, pattern)
+}
+
+// createChar creates a custom character and records its pattern
+func (lcd *CharLCDRGBI2C) createChar(location byte, pattern []byte) {
+	// Only positions 0-7 are allowed. The 5x10 font uses 16 bytes of CGRAM
+	// per character, which codes 2n and 2n+1 both show.
 	location &= 0x7
-	lcd.write8(LCD_SETCGRAMADDR | (location << 3))
-	for i := 0; i < 8; i++ {
-		lcd.write8(pattern[i], true)
+	address, rows := location<<3, 8
+	if lcd.font5x10 {
+		location &^= 0x1
+		address, rows = location<<3, 11
+	}
+	lcd.glyphs[location] = make([]byte, rows)
+	copy(lcd.glyphs[location], pattern)
+
+	// Every controller has its own CGRAM
+	lcd.fb.detach()
+	current := lcd.controller
+	for controller := range lcd.enablePins {
+		lcd.controller = controller
+		lcd.write8(LCD_SETCGRAMADDR | address)
+		for _, row := range lcd.glyphs[location] {
+			lcd.write8(row, true)
+		}
 	}
+	lcd.controller = current
 }
 
 // Message displays text on the LCD
+//
+// Text that runs past the last column wraps onto the next line, text that
+// runs past the last line is dropped.
 func (lcd *CharLCDRGBI2C) Message(message string) {
+	lcd.lock()
+	defer lcd.unlock()
+	lcd.writeMessage(context.Background(), message)
+}
+
+// writeMessage displays text on the LCD, stopping early when ctx is done
+func (lcd *CharLCDRGBI2C) writeMessage(ctx context.Context, message string) error {
 	lcd.message = message
 
+	leftToRight := lcd.displayMode&LCD_ENTRYLEFT > 0
+
 	// Set line to match current row
 	line := lcd.row
+	// Start at current position determined by text direction
+	col := lcd.column
+	if !leftToRight {
+		col = lcd.columns - 1 - lcd.column
+	}
+	// Column new lines start at
+	lineStart := func() int {
+		switch {
+		case lcd.columnAlign:
+			return lcd.column
+		case leftToRight:
+			return 0
+		default:
+			return lcd.columns - 1
+		}
+	}
 	// Track initial character
 	initialCharacter := 0
 
 	// Iterate through each character
+	var err error
 	for _, character := range message {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+
 		// If this is the first character in the string
 		if initialCharacter == 0 {
-			// Start at current position determined by text direction
-			var col int
-			if lcd.displayMode&LCD_ENTRYLEFT > 0 {
-				col = lcd.column
-			} else {
-				col = lcd.columns - 1 - lcd.column
-			}
-			lcd.CursorPosition(col, line)
+			lcd.cursorPosition(col, line)
 			initialCharacter++
 		}
 
-		// If character is newline, go to next line
-		if character == '\n' {
+		// If character is newline, or the line is full, go to next line
+		if character == '\n' || col < 0 || col >= lcd.columns {
 			line++
-			// Handle starting position on new line
-			var col int
-			if lcd.displayMode&LCD_ENTRYLEFT > 0 {
-				if lcd.columnAlign {
-					col = lcd.column
-				} else {
-					col = 0
-				}
-			} else {
-				if lcd.columnAlign {
-					col = lcd.column
-				} else {
-					col = lcd.columns - 1
-				}
+			if line >= lcd.lines {
+				break
+			}
+			col = lineStart()
+			lcd.cursorPosition(col, line)
+			if character == '\n' {
+				continue
 			}
-			lcd.CursorPosition(col, line)
+		}
+
+		// Write character to display
+		lcd.writeChar(byte(character))
+		if leftToRight {
+			col++
 		} else {
-			// Write character to display
-			lcd.write8(byte(character), true)
+			col--
 		}
 	}
 
 	// Reset column and row to (0,0) after message is displayed
 	lcd.column, lcd.row = 0, 0
+	return err
+}
+
+// writeChar writes a character at the cursor
+func (lcd *CharLCDRGBI2C) writeChar(character byte) {
+	lcd.write8(character, true)
+	lcd.fb.put(character, lcd.displayMode&LCD_ENTRYLEFT > 0)
+}
+
+// drawLine redraws a line from the framebuffer
+func (lcd *CharLCDRGBI2C) drawLine(row int) {
+	line := lcd.fb.cells[row]
+	if lcd.displayMode&LCD_ENTRYLEFT > 0 {
+		lcd.cursorPosition(0, row)
+		for column := 0; column < len(line); column++ {
+			lcd.writeChar(line[column])
+		}
+	} else {
+		lcd.cursorPosition(len(line)-1, row)
+		for column := len(line) - 1; column >= 0; column-- {
+			lcd.writeChar(line[column])
+		}
+	}
+}
+
+// command sends a command to every controller
+func (lcd *CharLCDRGBI2C) command(value byte) {
+	current := lcd.controller
+	for controller := range lcd.enablePins {
+		lcd.controller = controller
+		lcd.write8(value)
+	}
+	lcd.controller = current
 }
 
-// write8 sends 8-bit value to the LCD
+// updateDisplayControl writes the display control settings to every
+// controller, only the controller the cursor is on shows the cursor
+func (lcd *CharLCDRGBI2C) updateDisplayControl() {
+	current := lcd.controller
+	for controller := range lcd.enablePins {
+		lcd.controller = controller
+		control := lcd.displayControl
+		if controller != current {
+			control &^= LCD_CURSORON | LCD_BLINKON
+		}
+		lcd.write8(LCD_DISPLAYCONTROL | control)
+	}
+	lcd.controller = current
+}
+
+// write8 sends 8-bit value to the controller the cursor is on
 func (lcd *CharLCDRGBI2C) write8(value byte, charMode ...bool) {
 	// Default to command mode (false)
 	isCharMode := false
@@ -344,9 +697,15 @@ func (lcd *CharLCDRGBI2C) write8(value byte, charMode ...bool) {
 
 	// Set RS pin based on character/command mode
 	if isCharMode {
-		lcd.mcp.Set(mcp23017.Pins{LcdRsPin}).HIGH() // Character mode
+		lcd.track(lcd.pins.High(LcdRsPin)) // Character mode
 	} else {
-		lcd.mcp.Set(mcp23017.Pins{LcdRsPin}).LOW() // Command mode
+		lcd.track(lcd.pins.Low(LcdRsPin)) // Command mode
+	}
+
+	// Write all 8 bits at once on an 8-bit bus
+	if lcd.eightBit() {
+		lcd.writeBus(value)
+		return
 	}
 
 	// Write upper 4 bits
@@ -357,41 +716,43 @@ func (lcd *CharLCDRGBI2C) write8(value byte, charMode ...bool) {
 
 // write4bits sends 4-bits to the LCD
 func (lcd *CharLCDRGBI2C) write4bits(value byte) {
-	// Set data pins
-	if value&0x01 > 0 {
-		lcd.mcp.Set(mcp23017.Pins{LcdD4Pin}).HIGH()
-	} else {
-		lcd.mcp.Set(mcp23017.Pins{LcdD4Pin}).LOW()
-	}
+	lcd.writeBus(value)
+}
 
-	if value&0x02 > 0 {
-		lcd.mcp.Set(mcp23017.Pins{LcdD5Pin}).HIGH()
-	} else {
-		lcd.mcp.Set(mcp23017.Pins{LcdD5Pin}).LOW()
+// writeBus puts value on the data pins and latches it
+func (lcd *CharLCDRGBI2C) writeBus(value byte) {
+	// Set data pins, bit 0 is on the first data pin
+	var high, low []string
+	for bit, pin := range lcd.dataPins {
+		if value&(1<<bit) > 0 {
+			high = append(high, pin)
+		} else {
+			low = append(low, pin)
+		}
 	}
-
-	if value&0x04 > 0 {
-		lcd.mcp.Set(mcp23017.Pins{LcdD6Pin}).HIGH()
-	} else {
-		lcd.mcp.Set(mcp23017.Pins{LcdD6Pin}).LOW()
+	if len(high) > 0 {
+		lcd.track(lcd.pins.High(high...))
 	}
-
-	if value&0x08 > 0 {
-		lcd.mcp.Set(mcp23017.Pins{LcdD7Pin}).HIGH()
-	} else {
-		lcd.mcp.Set(mcp23017.Pins{LcdD7Pin}).LOW()
+	if len(low) > 0 {
+		lcd.track(lcd.pins.Low(low...))
 	}
 
 	// Pulse enable pin
 	lcd.pulseEnable()
 }
 
-// pulseEnable pulses the enable pin to latch command
+// eightBit reports whether the LCD is driven over an 8-bit data bus
+func (lcd *CharLCDRGBI2C) eightBit() bool {
+	return len(lcd.dataPins) == 8
+}
+
+// pulseEnable pulses the enable pin of the current controller to latch command
 func (lcd *CharLCDRGBI2C) pulseEnable() {
-	lcd.mcp.Set(mcp23017.Pins{LcdEnablePin}).LOW()
+	enablePin := lcd.enablePins[lcd.controller]
+	lcd.track(lcd.pins.Low(enablePin))
 	time.Sleep(1 * time.Microsecond)
-	lcd.mcp.Set(mcp23017.Pins{LcdEnablePin}).HIGH()
+	lcd.track(lcd.pins.High(enablePin))
 	time.Sleep(1 * time.Microsecond)
-	lcd.mcp.Set(mcp23017.Pins{LcdEnablePin}).LOW()
+	lcd.track(lcd.pins.Low(enablePin))
 	time.Sleep(100 * time.Microsecond) // Commands need > 37us to settle
 }
@@ -0,0 +1,71 @@
+package charLCDRGBI2C_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jyap808/charLCDRGBI2C"
+	"github.com/jyap808/charLCDRGBI2C/sim"
+)
+
+// TestConcurrentUse writes every line from its own goroutine while others
+// change the LED and read the buttons. Run with -race.
+func TestConcurrentUse(t *testing.T) {
+	for _, geometry := range []charLCDRGBI2C.Geometry{charLCDRGBI2C.Geometry16x2, charLCDRGBI2C.Geometry20x4} {
+		lcd, dev := sim.NewLCD(t, geometry, sim.Wiring{})
+		letters := "ABCD"[:geometry.Lines]
+
+		var wg sync.WaitGroup
+		for row := range geometry.Lines {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				line := strings.Repeat(letters[row:row+1], geometry.Columns)
+				for range 10 {
+					lcd.CursorPosition(0, row)
+					lcd.Message(line)
+				}
+			}()
+		}
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := range 50 {
+				lcd.SetColor(100*(i%2), 0, 100)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for range 50 {
+				dev.Press(charLCDRGBI2C.SelectButton)
+				if !lcd.IsButtonPressed(charLCDRGBI2C.SelectButton) {
+					t.Error("SelectButton not pressed")
+				}
+				dev.Release(charLCDRGBI2C.SelectButton)
+				if lcd.IsButtonPressed(charLCDRGBI2C.SelectButton) {
+					t.Error("SelectButton pressed after release")
+				}
+			}
+		}()
+		wg.Wait()
+
+		// Another goroutine can move the cursor between CursorPosition and
+		// Message, so a line may show another goroutine's letter or none at
+		// all, but never two letters or a character made of the nibbles of
+		// two different ones
+		text := lcd.Text()
+		for row, line := range dev.Codes() {
+			if string(line) != text[row] {
+				t.Errorf("%dx%d line %d shows %q, driver has %q", geometry.Columns, geometry.Lines, row, line, text[row])
+			}
+			if !strings.Contains(letters+" ", string(line[0])) || strings.Count(string(line), string(line[0])) != len(line) {
+				t.Errorf("%dx%d line %d is garbled: %q", geometry.Columns, geometry.Lines, row, line)
+			}
+		}
+		red, green, blue := dev.LED()
+		if !red || green || !blue {
+			t.Errorf("LED red %v green %v blue %v, want magenta", red, green, blue)
+		}
+	}
+}
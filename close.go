@@ -0,0 +1,91 @@
+package charLCDRGBI2C
+
+import (
+	"errors"
+
+	"github.com/googolgl/go-mcp23017"
+)
+
+// CloseOptions selects what Close leaves behind on the hardware
+type CloseOptions struct {
+	Clear        bool // Clear the display
+	LEDOff       bool // Turn the RGB LED off
+	BacklightOff bool // Turn the backlight off
+	ReleasePins  bool // Restore every MCP23017 pin to an input
+}
+
+// Close stops background goroutines and then applies opts. It does not close
+// the I2C device, which belongs to the caller. With WithStateFile the state
+// left on the display is saved last. Calling Close again does nothing and
+// returns the result of the first call.
+func (lcd *CharLCDRGBI2C) Close(opts CloseOptions) error {
+	lcd.closeOnce.Do(func() {
+		// Under the lock so that background starts nothing after this
+		lcd.mu.Lock()
+		close(lcd.done)
+		lcd.mu.Unlock()
+		lcd.wg.Wait()
+
+		lcd.mu.Lock()
+		defer lcd.mu.Unlock()
+		lcd.closeErr = lcd.shutdown(opts)
+	})
+	return lcd.closeErr
+}
+
+// shutdown applies opts, going on after errors so that as much as possible
+// is left behind as asked
+func (lcd *CharLCDRGBI2C) shutdown(opts CloseOptions) error {
+	var errs []error
+	if opts.Clear {
+		errs = append(errs, lcd.busStep(lcd.clear))
+	}
+	if opts.LEDOff {
+		errs = append(errs, lcd.busStep(func() { lcd.setColor(0, 0, 0) }))
+	}
+	if opts.BacklightOff {
+		errs = append(errs, lcd.setBacklight(false))
+	}
+	if opts.ReleasePins {
+		errs = append(errs, lcd.pins.PullDown(mcp23017.AllPins()...))
+		errs = append(errs, lcd.pins.Input(mcp23017.AllPins()...))
+	}
+	if lcd.stateFile != "" {
+		if lcd.saveTimer != nil {
+			lcd.saveTimer.Stop()
+		}
+		errs = append(errs, saveState(lcd.stateFile, lcd.snapshot()))
+	}
+	lcd.recovery.used, lcd.recovery.err = false, nil
+	return errors.Join(errs...)
+}
+
+// busStep runs fn and returns the first bus error it ran into, without
+// recovering from it
+func (lcd *CharLCDRGBI2C) busStep(fn func()) error {
+	lcd.recovery.err = nil
+	fn()
+	err := lcd.recovery.err
+	lcd.recovery.err = nil
+	return err
+}
+
+// background runs fn in a goroutine that Close waits for, done is closed
+// when Close is called. It reports whether fn was started, it is not once
+// the LCD is closed.
+func (lcd *CharLCDRGBI2C) background(fn func(done <-chan struct{})) bool {
+	lcd.mu.Lock()
+	defer lcd.mu.Unlock()
+	select {
+	case <-lcd.done:
+		return false
+	default:
+	}
+
+	lcd.wg.Add(1)
+	go func() {
+		defer lcd.wg.Done()
+		fn(lcd.done)
+	}()
+	return true
+}
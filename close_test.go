@@ -0,0 +1,45 @@
+package charLCDRGBI2C_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jyap808/charLCDRGBI2C"
+	"github.com/jyap808/charLCDRGBI2C/sim"
+)
+
+func TestClose(t *testing.T) {
+	lcd, dev := sim.NewLCD(t, charLCDRGBI2C.Geometry16x2, sim.Wiring{})
+	lcd.Message("Hello")
+	lcd.SetColor(100, 0, 0)
+
+	opts := charLCDRGBI2C.CloseOptions{Clear: true, LEDOff: true, BacklightOff: true}
+	if err := lcd.Close(opts); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if got := string(dev.Codes()[0]); got != "                " {
+		t.Errorf("line 0 is %q after Close, want it cleared", got)
+	}
+	if red, green, blue := dev.LED(); red || green || blue || dev.Backlight() {
+		t.Errorf("LED red %v green %v blue %v, backlight %v after Close, want all off", red, green, blue, dev.Backlight())
+	}
+
+	// A second Close leaves the board alone
+	dev.Unplug()
+	if err := lcd.Close(charLCDRGBI2C.CloseOptions{ReleasePins: true}); err != nil {
+		t.Errorf("second Close: %v", err)
+	}
+}
+
+func TestCloseErrors(t *testing.T) {
+	lcd, dev := sim.NewLCD(t, charLCDRGBI2C.Geometry16x2, sim.Wiring{})
+	dev.Unplug()
+
+	err := lcd.Close(charLCDRGBI2C.CloseOptions{Clear: true, LEDOff: true})
+	if !errors.Is(err, sim.ErrUnplugged) {
+		t.Fatalf("Close on an unplugged board returned %v, want %v", err, sim.ErrUnplugged)
+	}
+	if again := lcd.Close(charLCDRGBI2C.CloseOptions{}); again == nil || again.Error() != err.Error() {
+		t.Errorf("second Close returned %v, want %v", again, err)
+	}
+}
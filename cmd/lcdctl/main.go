@@ -0,0 +1,333 @@
+// Command lcdctl drives the LCD from shell scripts.
+//
+// Usage:
+//
+//	lcdctl [flags] command [arguments]
+//
+// The commands are:
+//
+//	write [-row N] [-column N] text...   write text, "\n" starts a new line
+//	clear                                clear the display
+//	color red green blue | #RRGGBB       set the RGB LED, values from 0-100
+//	backlight on|off                     turn the backlight on or off
+//	cursor on|off|blink|noblink          show or hide the cursor
+//	char location row...                 define a custom character
+//	wait-button [-timeout D]             print the name of the next button pressed
+//
+// The display keeps its contents from one run to the next: the state is
+// saved to a file, under the user's cache directory unless -state gives
+// another, and a board that kept its setup is taken over without clearing
+// it. -init initializes the display instead, which clears it. With -sim the
+// commands run against a simulated board that is printed afterwards, no
+// hardware needed; -press holds a button down on it for wait-button, which
+// gives up after a second there unless -timeout says otherwise.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/googolgl/go-i2c"
+	"github.com/googolgl/go-mcp23017"
+	"github.com/jyap808/charLCDRGBI2C"
+	"github.com/jyap808/charLCDRGBI2C/sim"
+)
+
+// board is a known board layout
+type board struct {
+	geometry string
+	opts     []charLCDRGBI2C.Option
+}
+
+// boards by name
+var boards = map[string]board{
+	"rgb1602": {geometry: "16x2"},
+}
+
+var (
+	bus       = flag.String("bus", "/dev/i2c-1", "I2C bus device")
+	address   = flag.Uint("address", uint(mcp23017.DefI2CAdr), "I2C address of the MCP23017")
+	geometry  = flag.String("geometry", "", "panel size as COLUMNSxLINES, defaults to the board's")
+	boardName = flag.String("board", "rgb1602", "board profile")
+	simulate  = flag.Bool("sim", false, "use a simulated board and print it")
+	stateFile = flag.String("state", "", "keep the display contents in `file` between runs, defaults to a file in the user's cache directory")
+	initLCD   = flag.Bool("init", false, "initialize the display, which clears it, instead of taking over what it shows")
+	press     = flag.String("press", "", "with -sim, hold down `button` (left, up, down, right or select)")
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("lcdctl: ")
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+	os.Exit(run())
+}
+
+// run runs the command line and returns the exit code, closing the LCD
+// whatever happens so that its state is saved
+func run() int {
+	lcd, dev, closeLCD, err := open()
+	if err != nil {
+		log.Print(err)
+		return 1
+	}
+	defer closeLCD()
+
+	if err := execute(os.Stdout, lcd, dev, flag.Arg(0), flag.Args()[1:]); err != nil {
+		log.Print(err)
+		return 1
+	}
+	if dev != nil {
+		fmt.Print(dev)
+	}
+	return 0
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, `usage: lcdctl [flags] command [arguments]
+
+commands:
+  write [-row N] [-column N] text...   write text, "\n" starts a new line
+  clear                                clear the display
+  color red green blue | #RRGGBB       set the RGB LED, values from 0-100
+  backlight on|off                     turn the backlight on or off
+  cursor on|off|blink|noblink          show or hide the cursor
+  char location row...                 define a custom character
+  wait-button [-timeout D]             print the name of the next button pressed
+
+flags:
+`)
+	flag.PrintDefaults()
+}
+
+// open creates the LCD on the I2C bus, or on a simulated board with -sim
+func open() (*charLCDRGBI2C.CharLCDRGBI2C, *sim.Device, func(), error) {
+	b, ok := boards[*boardName]
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("unknown board %q", *boardName)
+	}
+	size := b.geometry
+	if *geometry != "" {
+		size = *geometry
+	}
+	var columns, lines int
+	if _, err := fmt.Sscanf(size, "%dx%d", &columns, &lines); err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid geometry %q", size)
+	}
+	g, err := charLCDRGBI2C.LookupGeometry(columns, lines)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// Without a saved state the display is initialized
+	state := *stateFile
+	if state == "" {
+		var err error
+		if state, err = defaultStateFile(); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	if *initLCD {
+		if err := os.Remove(state); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, nil, nil, err
+		}
+	}
+	opts := append(b.opts[:len(b.opts):len(b.opts)], charLCDRGBI2C.WithStateFile(state))
+
+	if *simulate {
+		dev := sim.New(g, sim.Wiring{})
+		if *press != "" {
+			button, ok := charLCDRGBI2C.ButtonByName(*press)
+			if !ok {
+				return nil, nil, nil, fmt.Errorf("unknown button %q", *press)
+			}
+			dev.Press(button)
+		}
+		lcd, err := charLCDRGBI2C.NewWithDriver(dev, g, opts...)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return lcd, dev, func() {
+			shutdown(lcd)
+		}, nil
+	}
+
+	i2c, err := i2c.New(uint8(*address), *bus)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to initialize I2C: %v", err)
+	}
+	lcd, err := charLCDRGBI2C.NewWithGeometry(i2c, g, opts...)
+	if err != nil {
+		i2c.Close()
+		return nil, nil, nil, err
+	}
+	return lcd, nil, func() {
+		shutdown(lcd)
+		i2c.Close()
+	}, nil
+}
+
+// defaultStateFile returns the state file used without -state, in a
+// directory of the user's cache directory only they can write to
+func defaultStateFile() (string, error) {
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("no state file, pass -state: %w", err)
+	}
+	dir := filepath.Join(cache, "lcdctl")
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+	name := fmt.Sprintf("%s-%#x.json", filepath.Base(*bus), *address)
+	if *simulate {
+		name = "sim.json"
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// shutdown closes the LCD, leaving it as it is
+func shutdown(lcd *charLCDRGBI2C.CharLCDRGBI2C) {
+	if err := lcd.Close(charLCDRGBI2C.CloseOptions{}); err != nil {
+		log.Print(err)
+	}
+}
+
+// execute runs one command, writing its output to w
+func execute(w io.Writer, lcd *charLCDRGBI2C.CharLCDRGBI2C, dev *sim.Device, command string, args []string) error {
+	switch command {
+	case "write":
+		fs := flag.NewFlagSet("write", flag.ContinueOnError)
+		row := fs.Int("row", 0, "row to start at")
+		column := fs.Int("column", 0, "column to start at")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		text := strings.ReplaceAll(strings.Join(fs.Args(), " "), `\n`, "\n")
+		lcd.CursorPosition(*column, *row)
+		lcd.Message(text)
+
+	case "clear":
+		lcd.Clear()
+
+	case "color":
+		return color(lcd, args)
+
+	case "backlight":
+		on, err := onOff(args)
+		if err != nil {
+			return err
+		}
+		return lcd.SetBacklight(on)
+
+	case "cursor":
+		if len(args) != 1 {
+			return errors.New("usage: cursor on|off|blink|noblink")
+		}
+		switch args[0] {
+		case "on", "off":
+			lcd.SetCursor(args[0] == "on")
+		case "blink", "noblink":
+			lcd.SetBlink(args[0] == "blink")
+		default:
+			return fmt.Errorf("invalid cursor state %q", args[0])
+		}
+
+	case "char":
+		if len(args) < 2 {
+			return errors.New("usage: char location row...")
+		}
+		location, err := strconv.ParseUint(args[0], 0, 8)
+		if err != nil || location > 7 {
+			return fmt.Errorf("invalid location %q", args[0])
+		}
+		var pattern []byte
+		for _, arg := range args[1:] {
+			row, err := strconv.ParseUint(arg, 0, 8)
+			if err != nil {
+				return fmt.Errorf("invalid row %q", arg)
+			}
+			pattern = append(pattern, byte(row))
+		}
+		lcd.CreateChar(byte(location), pattern)
+
+	case "wait-button":
+		fs := flag.NewFlagSet("wait-button", flag.ContinueOnError)
+		timeout := fs.Duration("timeout", 0, "give up after this long, zero waits forever")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		if dev != nil && *timeout == 0 {
+			// Nobody presses the buttons of a simulated board
+			*timeout = time.Second
+		}
+		return waitButton(w, lcd, *timeout)
+
+	default:
+		return fmt.Errorf("unknown command %q", command)
+	}
+	return nil
+}
+
+// color sets the LED from three 0-100 values or a #RRGGBB color
+func color(lcd *charLCDRGBI2C.CharLCDRGBI2C, args []string) error {
+	if len(args) == 1 && strings.HasPrefix(args[0], "#") {
+		rgb, err := strconv.ParseUint(args[0][1:], 16, 24)
+		if err != nil {
+			return fmt.Errorf("invalid color %q", args[0])
+		}
+		lcd.SetColorRGB(int(rgb))
+		return nil
+	}
+	if len(args) != 3 {
+		return errors.New("usage: color red green blue | #RRGGBB")
+	}
+	var values [3]int
+	for i, arg := range args {
+		value, err := strconv.Atoi(arg)
+		if err != nil || value < 0 || value > 100 {
+			return fmt.Errorf("invalid color value %q", arg)
+		}
+		values[i] = value
+	}
+	lcd.SetColor(values[0], values[1], values[2])
+	return nil
+}
+
+// waitButton prints the name of the next button pressed
+func waitButton(w io.Writer, lcd *charLCDRGBI2C.CharLCDRGBI2C, timeout time.Duration) error {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	for event := range lcd.WatchButtons(ctx, 20*time.Millisecond) {
+		if event.Pressed {
+			fmt.Fprintln(w, charLCDRGBI2C.ButtonName(event.Button))
+			return nil
+		}
+	}
+	return errors.New("timed out waiting for a button")
+}
+
+// onOff parses a single on or off argument
+func onOff(args []string) (bool, error) {
+	if len(args) == 1 && (args[0] == "on" || args[0] == "off") {
+		return args[0] == "on", nil
+	}
+	return false, errors.New("expected on or off")
+}
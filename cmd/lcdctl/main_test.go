@@ -0,0 +1,138 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jyap808/charLCDRGBI2C"
+	"github.com/jyap808/charLCDRGBI2C/sim"
+)
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		command string
+		args    []string
+		want    string // Line 0
+	}{
+		{"write", []string{"Hello", "World"}, "Hello World"},
+		{"write", []string{`One\nTwo`}, "One"},
+		{"write", []string{"-column", "3", "Hi"}, "OldHi"},
+		{"clear", nil, ""},
+	}
+	for _, tt := range tests {
+		lcd, dev := sim.NewLCD(t, charLCDRGBI2C.Geometry16x2, sim.Wiring{})
+		lcd.Message("Old")
+		if err := execute(nil, lcd, dev, tt.command, tt.args); err != nil {
+			t.Errorf("%s %q: %v", tt.command, tt.args, err)
+			continue
+		}
+		if got := strings.TrimRight(string(dev.Codes()[0]), " "); got != tt.want {
+			t.Errorf("%s %q: line 0 shows %q, want %q", tt.command, tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestExecuteErrors(t *testing.T) {
+	tests := []struct {
+		command string
+		args    []string
+		want    string
+	}{
+		{"color", []string{"0", "100"}, "usage: color red green blue | #RRGGBB"},
+		{"color", []string{"0", "101", "0"}, `invalid color value "101"`},
+		{"color", []string{"#GG0000"}, `invalid color "#GG0000"`},
+		{"backlight", []string{"dim"}, "expected on or off"},
+		{"cursor", []string{"underline"}, `invalid cursor state "underline"`},
+		{"char", []string{"8", "0"}, `invalid location "8"`},
+		{"char", []string{"0", "0x100"}, `invalid row "0x100"`},
+		{"reboot", nil, `unknown command "reboot"`},
+	}
+	lcd, dev := sim.NewLCD(t, charLCDRGBI2C.Geometry16x2, sim.Wiring{})
+	for _, tt := range tests {
+		err := execute(nil, lcd, dev, tt.command, tt.args)
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("%s %q: got %v, want %s", tt.command, tt.args, err, tt.want)
+		}
+	}
+}
+
+func TestExecuteSettings(t *testing.T) {
+	lcd, dev := sim.NewLCD(t, charLCDRGBI2C.Geometry16x2, sim.Wiring{})
+	commands := [][]string{
+		{"color", "100", "0", "100"},
+		{"backlight", "off"},
+		{"cursor", "on"},
+		{"char", "0", "0x1f", "0", "0x1f"},
+	}
+	for _, command := range commands {
+		if err := execute(nil, lcd, dev, command[0], command[1:]); err != nil {
+			t.Fatalf("%q: %v", command, err)
+		}
+	}
+	if red, green, blue := dev.LED(); !red || green || !blue {
+		t.Errorf("LED red %v green %v blue %v, want magenta", red, green, blue)
+	}
+	if dev.Backlight() {
+		t.Error("backlight on")
+	}
+	if !dev.Controller(0).CursorOn {
+		t.Error("cursor off")
+	}
+	if glyph := dev.Glyph(0, 0); glyph[0] != 0x1f || glyph[1] != 0 || glyph[2] != 0x1f {
+		t.Errorf("character 0 is %#x", glyph)
+	}
+}
+
+func TestWaitButton(t *testing.T) {
+	lcd, dev := sim.NewLCD(t, charLCDRGBI2C.Geometry16x2, sim.Wiring{})
+	dev.Press(charLCDRGBI2C.SelectButton)
+	var out strings.Builder
+	if err := execute(&out, lcd, dev, "wait-button", nil); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "select\n" {
+		t.Errorf("printed %q, want select", out.String())
+	}
+
+	// Without a press the simulated board gives up rather than hang
+	dev.Release(charLCDRGBI2C.SelectButton)
+	if err := execute(&out, lcd, dev, "wait-button", []string{"-timeout", "50ms"}); err == nil {
+		t.Error("no error without a press")
+	}
+}
+
+func TestStateKeptBetweenRuns(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	*simulate = true
+	defer func() { *simulate = false }()
+
+	state, err := defaultStateFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(filepath.Dir(state)); err != nil || info.Mode().Perm() != 0o700 {
+		t.Errorf("state directory %s: %v %v, want private", filepath.Dir(state), info.Mode(), err)
+	}
+
+	for i, want := range []string{"Hello", "Hello"} {
+		lcd, dev, closeLCD, err := open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i == 0 {
+			if err := execute(nil, lcd, dev, "write", []string{"Hello"}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if line := strings.TrimRight(string(dev.Codes()[0]), " "); line != want {
+			t.Errorf("run %d shows %q, want %q", i, line, want)
+		}
+		closeLCD()
+	}
+	if _, err := os.Stat(state); err != nil {
+		t.Error(err)
+	}
+}
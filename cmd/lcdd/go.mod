@@ -0,0 +1,16 @@
+module github.com/jyap808/charLCDRGBI2C/cmd/lcdd
+
+go 1.23.6
+
+require (
+	github.com/googolgl/go-i2c v0.1.1
+	github.com/googolgl/go-mcp23017 v0.0.0-20210225115400-eb8b77d91034
+	github.com/jyap808/charLCDRGBI2C v0.1.0
+	github.com/jyap808/charLCDRGBI2C/emulator v0.1.0
+)
+
+require (
+	github.com/sirupsen/logrus v1.8.1 // indirect
+	golang.org/x/sys v0.33.0 // indirect
+	golang.org/x/term v0.32.0 // indirect
+)
@@ -0,0 +1,177 @@
+// Command lcdd owns the LCD and shares it with other processes over a Unix
+// domain socket, see package daemon for the protocol.
+//
+// Usage:
+//
+//	lcdd [flags]
+//
+// With -sim the daemon drives a simulated board that is printed when the
+// daemon exits, no hardware needed. -emulate shows it live in the terminal
+// instead, where the arrow keys and Enter work the buttons. With -record
+// the bus traffic is written to a file that lcdreplay shows the panel from.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/googolgl/go-i2c"
+	"github.com/googolgl/go-mcp23017"
+	"github.com/jyap808/charLCDRGBI2C"
+	"github.com/jyap808/charLCDRGBI2C/daemon"
+	"github.com/jyap808/charLCDRGBI2C/emulator"
+	"github.com/jyap808/charLCDRGBI2C/record"
+	"github.com/jyap808/charLCDRGBI2C/sim"
+)
+
+var (
+	socket    = flag.String("socket", "/run/lcdd.sock", "Unix domain socket to listen on")
+	mode      = flag.String("socket-mode", "0600", "permissions of the socket, 0660 lets the socket's group in too")
+	bus       = flag.String("bus", "/dev/i2c-1", "I2C bus device")
+	address   = flag.Uint("address", uint(mcp23017.DefI2CAdr), "I2C address of the MCP23017")
+	geometry  = flag.String("geometry", "16x2", "panel size as COLUMNSxLINES")
+	simulate  = flag.Bool("sim", false, "use a simulated board and print it on exit")
+	emulate   = flag.Bool("emulate", false, "show a simulated board in the terminal, the keys work its buttons")
+	recording = flag.String("record", "", "record the bus traffic to `file`, see lcdreplay")
+	flush     = flag.Duration("flush", 100*time.Millisecond, "how often to write the recording out, so that a crash loses little of it")
+	watch     = flag.Duration("watch", 2*time.Second, "how often to check the board is there and set up, 0 to never")
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("lcdd: ")
+	flag.Parse()
+
+	var columns, lines int
+	if _, err := fmt.Sscanf(*geometry, "%dx%d", &columns, &lines); err != nil {
+		log.Fatalf("invalid geometry %q", *geometry)
+	}
+	g, err := charLCDRGBI2C.LookupGeometry(columns, lines)
+	if err != nil {
+		log.Fatal(err)
+	}
+	socketMode, err := strconv.ParseUint(*mode, 8, 32)
+	if err != nil || socketMode > 0o777 {
+		log.Fatalf("invalid socket mode %q", *mode)
+	}
+
+	var driver charLCDRGBI2C.PinDriver
+	var dev *sim.Device
+	var emu *emulator.Emulator
+	switch {
+	case *emulate:
+		if emu, err = emulator.Open(g, sim.Wiring{}); err != nil {
+			log.Fatal(err)
+		}
+		log.SetOutput(emu)
+		driver = emu
+	case *simulate:
+		dev = sim.New(g, sim.Wiring{})
+		driver = dev
+	default:
+		device, err := i2c.New(uint8(*address), *bus)
+		if err != nil {
+			log.Fatalf("failed to initialize I2C: %v", err)
+		}
+		defer device.Close()
+		if driver, err = charLCDRGBI2C.NewMCP23017Driver(device); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	var rec *record.Recorder
+	if *recording != "" {
+		f, err := os.Create(*recording)
+		if err == nil {
+			rec, err = record.NewRecorder(driver, f)
+		}
+		if err != nil {
+			closeEmulator(emu)
+			log.Fatal(err)
+		}
+		driver = rec
+	}
+
+	lcd, err := charLCDRGBI2C.NewWithDriver(driver, g)
+	if err != nil {
+		closeEmulator(emu)
+		log.Fatal(err)
+	}
+	lcd.SetBacklight(true)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	flushed := make(chan struct{})
+	flushCtx, stopFlush := context.WithCancel(ctx)
+	go func() {
+		defer close(flushed)
+		if rec != nil {
+			flushEvery(flushCtx, rec, *flush)
+		}
+	}()
+
+	if *watch > 0 {
+		go func() {
+			for event := range lcd.WatchBoard(ctx, *watch) {
+				if event.Err != nil {
+					log.Printf("board %v: %v", event.Status, event.Err)
+				} else {
+					log.Printf("board %v", event.Status)
+				}
+			}
+		}()
+	}
+
+	server := daemon.NewServer(lcd)
+	server.SocketMode = os.FileMode(socketMode)
+	err = server.ListenAndServe(ctx, *socket)
+	lcd.Close(charLCDRGBI2C.CloseOptions{Clear: true, LEDOff: true, BacklightOff: true})
+	stopFlush()
+	<-flushed
+	if rec != nil {
+		if err := rec.Close(); err != nil {
+			log.Printf("recording: %v", err)
+		}
+	}
+	closeEmulator(emu)
+	if dev != nil {
+		fmt.Print(dev)
+	}
+	if err != nil && !errors.Is(err, context.Canceled) {
+		log.Fatal(err)
+	}
+}
+
+// flushEvery writes the recording out every interval until ctx is done
+func flushEvery(ctx context.Context, rec *record.Recorder, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := rec.Flush(); err != nil {
+				log.Printf("recording: %v", err)
+				return
+			}
+		}
+	}
+}
+
+// closeEmulator puts the terminal back when the emulator runs
+func closeEmulator(emu *emulator.Emulator) {
+	if emu != nil {
+		log.SetOutput(os.Stderr)
+		emu.Close()
+	}
+}
@@ -0,0 +1,153 @@
+// Command lcdreplay shows what the panel showed during a recording made
+// with package record, by replaying it into a simulated board.
+//
+// Usage:
+//
+//	lcdreplay [flags] recording
+//
+// Without flags the panel is printed as it was at the end of the
+// recording. -at picks an earlier moment, -dump lists the recorded
+// operations instead. With -golden the panel is compared with a file, for
+// regression tests, and -update writes the file. -png draws the panel
+// with its real font and backlight color. A recording cut short, by a
+// crash for instance, is shown up to where it ends and the exit status is
+// 1.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/jyap808/charLCDRGBI2C"
+	"github.com/jyap808/charLCDRGBI2C/record"
+	"github.com/jyap808/charLCDRGBI2C/render"
+	"github.com/jyap808/charLCDRGBI2C/sim"
+)
+
+var (
+	geometry = flag.String("geometry", "16x2", "panel size as COLUMNSxLINES")
+	enable2  = flag.String("enable2", "", "second enable `pin` of dual-controller panels such as 40x4")
+	at       = flag.Duration("at", -1, "show the panel this long into the recording, instead of at its end")
+	dump     = flag.Bool("dump", false, "list the recorded operations")
+	golden   = flag.String("golden", "", "compare the panel with `file` and fail when it differs")
+	update   = flag.Bool("update", false, "with -golden, write the panel to the file")
+	pngFile  = flag.String("png", "", "also draw the panel as a PNG image to `file`")
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("lcdreplay: ")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: lcdreplay [flags] recording\n\nflags:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	f, err := os.Open(flag.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+	r, err := record.NewReader(f)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *dump {
+		if err := dumpEvents(r); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	var columns, lines int
+	if _, err := fmt.Sscanf(*geometry, "%dx%d", &columns, &lines); err != nil {
+		log.Fatalf("invalid geometry %q", *geometry)
+	}
+	g, err := charLCDRGBI2C.LookupGeometry(columns, lines)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var wiring sim.Wiring
+	if *enable2 != "" {
+		wiring.EnablePins = []string{charLCDRGBI2C.LcdEnablePin, *enable2}
+	}
+
+	dev := sim.New(g, wiring)
+	last, err := record.Replay(r, dev, *at)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		log.Fatal(err)
+	}
+	if err := show(dev, last); err != nil {
+		log.Fatal(err)
+	}
+	if err != nil {
+		// Cut short, the panel is shown up to where it ends
+		log.Fatal(err)
+	}
+}
+
+// show prints the replayed panel, or compares it with or writes it to the
+// golden file
+func show(dev *sim.Device, last record.Event) error {
+	if *pngFile != "" {
+		if err := writePNG(*pngFile, dev); err != nil {
+			return err
+		}
+	}
+	panel := fmt.Sprintf("at %v\n%v", last.Time.Truncate(time.Microsecond), dev)
+
+	if *golden == "" {
+		fmt.Print(panel)
+		return nil
+	}
+	if *update {
+		return os.WriteFile(*golden, []byte(dev.String()), 0o644)
+	}
+	want, err := os.ReadFile(*golden)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(want, []byte(dev.String())) {
+		fmt.Printf("want\n%s\ngot %s", want, panel)
+		return errors.New("panel differs from " + *golden)
+	}
+	return nil
+}
+
+// writePNG draws the panel to a file
+func writePNG(path string, dev *sim.Device) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := render.PNG(f, dev, render.Options{}); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// dumpEvents prints one line per recorded operation
+func dumpEvents(r *record.Reader) error {
+	for {
+		e, err := r.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Println(e)
+	}
+}
@@ -0,0 +1,67 @@
+package charLCDRGBI2C
+
+import (
+	"context"
+	"time"
+)
+
+// ClearContext clears the LCD display unless ctx is already done
+func (lcd *CharLCDRGBI2C) ClearContext(ctx context.Context) error {
+	lcd.lock()
+	defer lcd.unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	lcd.clear()
+	return nil
+}
+
+// MessageContext displays text on the LCD like Message, stopping before the
+// next character when ctx is done
+func (lcd *CharLCDRGBI2C) MessageContext(ctx context.Context, message string) error {
+	lcd.lock()
+	defer lcd.unlock()
+	return lcd.writeMessage(ctx, message)
+}
+
+// ScrollText moves the displayed text one column every delay, left for
+// positive steps and right for negative steps, until done or ctx is done
+func (lcd *CharLCDRGBI2C) ScrollText(ctx context.Context, steps int, delay time.Duration) error {
+	move := byte(LCD_MOVELEFT)
+	if steps < 0 {
+		move = LCD_MOVERIGHT
+		steps = -steps
+	}
+
+	for i := 0; i < steps; i++ {
+		// Only hold the bus for the move itself
+		lcd.lock()
+		err := ctx.Err()
+		if err == nil {
+			lcd.command(LCD_CURSORSHIFT | LCD_DISPLAYMOVE | move)
+		}
+		lcd.unlock()
+		if err != nil {
+			return err
+		}
+
+		if err := sleepContext(ctx, delay); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sleepContext pauses for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,371 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/jyap808/charLCDRGBI2C"
+)
+
+// ErrClosed is returned for requests on a closed connection
+var ErrClosed = errors.New("daemon connection closed")
+
+// Client is a connection to a Server. It implements charLCDRGBI2C.Panel,
+// drawing on the client's virtual screen which the daemon shows while the
+// client owns the display.
+//
+// The Display methods do not return errors, the first failed request is
+// kept and returned by Err.
+type Client struct {
+	conn    net.Conn
+	columns int
+	lines   int
+	done    chan struct{} // Closed when the connection is closed
+
+	writeMu sync.Mutex // Serializes requests on conn
+
+	mu          sync.Mutex
+	nextID      int
+	pending     map[int]chan Reply
+	watchers    map[*watcher]bool
+	subscribed  bool
+	allEvents   bool
+	closed      bool
+	err         error
+	row, column int // Cursor position the next Message starts at
+}
+
+// watcher receives button events for WatchButtons
+type watcher struct {
+	events chan charLCDRGBI2C.ButtonEvent
+	all    bool // Also receive events while not owning the screen
+}
+
+var _ charLCDRGBI2C.Panel = (*Client)(nil)
+
+// Dial connects to the daemon listening on the Unix domain socket path
+func Dial(path string) (*Client, error) {
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{
+		conn:     conn,
+		done:     make(chan struct{}),
+		pending:  make(map[int]chan Reply),
+		watchers: make(map[*watcher]bool),
+	}
+	go c.readLoop()
+
+	reply, err := c.do(Request{Op: "size"})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	c.columns, c.lines = reply.Columns, reply.Lines
+	return c, nil
+}
+
+// Close closes the connection, the daemon hands the screen to the next
+// client
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Err returns the first error of a request made through a method that does
+// not return errors
+func (c *Client) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// Claim claims the screen with a priority. The client with the highest
+// priority owns the screen, the newest claim wins between equal priorities.
+func (c *Client) Claim(priority int) error {
+	_, err := c.do(Request{Op: "claim", Priority: priority})
+	return err
+}
+
+// Release gives up the screen until the client claims it or draws again
+func (c *Client) Release() error {
+	_, err := c.do(Request{Op: "release"})
+	return err
+}
+
+// Owner reports whether the client owns the screen
+func (c *Client) Owner() (bool, error) {
+	reply, err := c.do(Request{Op: "state"})
+	return reply.Owner, err
+}
+
+// Size returns the number of columns and lines of the LCD
+func (c *Client) Size() (columns, lines int) {
+	return c.columns, c.lines
+}
+
+// Clear clears the screen
+func (c *Client) Clear() {
+	c.mu.Lock()
+	c.row, c.column = 0, 0
+	c.mu.Unlock()
+	c.send(Request{Op: "clear"})
+}
+
+// CursorPosition moves the cursor, the next Message starts there
+func (c *Client) CursorPosition(column, row int) {
+	column = min(max(column, 0), c.columns-1)
+	row = min(max(row, 0), c.lines-1)
+
+	c.mu.Lock()
+	c.row, c.column = row, column
+	c.mu.Unlock()
+	c.send(Request{Op: "position", Row: row, Column: column})
+}
+
+// Message displays text at the cursor like CharLCDRGBI2C.Message
+func (c *Client) Message(message string) {
+	c.mu.Lock()
+	row, column := c.row, c.column
+	c.row, c.column = 0, 0
+	c.mu.Unlock()
+	c.send(Request{Op: "write", Row: row, Column: column, Text: message})
+}
+
+// CreateChar defines a custom character. The daemon uploads the glyphs of a
+// client whenever it is given the screen.
+func (c *Client) CreateChar(location byte, pattern []byte) {
+	rows := make([]int, len(pattern))
+	for i, row := range pattern {
+		rows[i] = int(row)
+	}
+	c.send(Request{Op: "glyph", Location: location, Pattern: rows})
+}
+
+// SetCursor shows or hides the cursor
+func (c *Client) SetCursor(show bool) {
+	c.send(Request{Op: "cursor", On: show})
+}
+
+// SetBlink turns cursor blinking on or off
+func (c *Client) SetBlink(blink bool) {
+	c.send(Request{Op: "blink", On: blink})
+}
+
+// SetDisplay turns the display on or off
+func (c *Client) SetDisplay(enable bool) {
+	c.send(Request{Op: "display", On: enable})
+}
+
+// SetColor sets the RGB LED color (values from 0-100)
+func (c *Client) SetColor(red, green, blue int) {
+	c.send(Request{Op: "color", Color: &[3]int{red, green, blue}})
+}
+
+// SetBacklight turns the backlight on or off. The backlight is shared by
+// every client.
+func (c *Client) SetBacklight(on bool) error {
+	_, err := c.do(Request{Op: "backlight", On: on})
+	return err
+}
+
+// Text returns the characters on each line of the client's screen
+func (c *Client) Text() []string {
+	reply, err := c.do(Request{Op: "state"})
+	if err != nil {
+		c.fail(err)
+		return nil
+	}
+	return reply.Text
+}
+
+// Color returns the RGB LED color of the client's screen
+func (c *Client) Color() (red, green, blue int) {
+	reply, err := c.do(Request{Op: "state"})
+	if err != nil || reply.Color == nil {
+		c.fail(err)
+		return 0, 0, 0
+	}
+	return reply.Color[0], reply.Color[1], reply.Color[2]
+}
+
+// Backlight reports whether the backlight is on
+func (c *Client) Backlight() bool {
+	reply, err := c.do(Request{Op: "state"})
+	c.fail(err)
+	return reply.Backlight
+}
+
+// WatchButtons sends an event for every press and release while the client
+// owns the screen. The daemon polls the buttons, interval is ignored. The
+// channel is closed when ctx is done or the connection is closed.
+//
+// Events are dropped while the channel is full, keep reading it.
+func (c *Client) WatchButtons(ctx context.Context, interval time.Duration) <-chan charLCDRGBI2C.ButtonEvent {
+	return c.watch(ctx, false)
+}
+
+// WatchAllButtons is WatchButtons including the events while another client
+// owns the screen
+func (c *Client) WatchAllButtons(ctx context.Context) <-chan charLCDRGBI2C.ButtonEvent {
+	return c.watch(ctx, true)
+}
+
+// watch registers a watcher and subscribes to the events it needs
+func (c *Client) watch(ctx context.Context, all bool) <-chan charLCDRGBI2C.ButtonEvent {
+	w := &watcher{events: make(chan charLCDRGBI2C.ButtonEvent, 16), all: all}
+
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		close(w.events)
+		return w.events
+	}
+	c.watchers[w] = true
+	subscribe := !c.subscribed || all && !c.allEvents
+	c.subscribed = true
+	c.allEvents = c.allEvents || all
+	c.mu.Unlock()
+
+	if subscribe {
+		c.send(Request{Op: "subscribe", All: all})
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-c.done:
+			return // readLoop closed the channel
+		}
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if c.watchers[w] {
+			delete(c.watchers, w)
+			close(w.events)
+		}
+	}()
+	return w.events
+}
+
+// send makes a request that has no reply value, keeping the error for Err
+func (c *Client) send(req Request) {
+	_, err := c.do(req)
+	c.fail(err)
+}
+
+// fail keeps the first error
+func (c *Client) fail(err error) {
+	if err == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err == nil {
+		c.err = err
+	}
+}
+
+// do makes a request and waits for its reply
+func (c *Client) do(req Request) (Reply, error) {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return Reply{}, ErrClosed
+	}
+	c.nextID++
+	req.ID = c.nextID
+	done := make(chan Reply, 1)
+	c.pending[req.ID] = done
+	c.mu.Unlock()
+
+	line, err := json.Marshal(req)
+	if err != nil {
+		c.forget(req.ID)
+		return Reply{}, err
+	}
+	c.writeMu.Lock()
+	_, err = c.conn.Write(append(line, '\n'))
+	c.writeMu.Unlock()
+	if err != nil {
+		c.forget(req.ID)
+		return Reply{}, err
+	}
+
+	reply, ok := <-done
+	if !ok {
+		return Reply{}, ErrClosed
+	}
+	if reply.Error != "" {
+		return reply, errors.New(reply.Error)
+	}
+	return reply, nil
+}
+
+// forget drops a pending request
+func (c *Client) forget(id int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.pending, id)
+}
+
+// readLoop dispatches replies and events until the connection closes
+func (c *Client) readLoop() {
+	scanner := bufio.NewScanner(c.conn)
+	for scanner.Scan() {
+		var reply Reply
+		if err := json.Unmarshal(scanner.Bytes(), &reply); err != nil {
+			continue
+		}
+
+		c.mu.Lock()
+		if reply.Event == "button" {
+			c.dispatch(reply)
+		} else if done, ok := c.pending[reply.ID]; ok {
+			delete(c.pending, reply.ID)
+			done <- reply
+		}
+		c.mu.Unlock()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	close(c.done)
+	for id, done := range c.pending {
+		delete(c.pending, id)
+		close(done)
+	}
+	for w := range c.watchers {
+		delete(c.watchers, w)
+		close(w.events)
+	}
+}
+
+// dispatch sends a button event to the watchers that want it. c.mu must be
+// held.
+func (c *Client) dispatch(reply Reply) {
+	button, ok := charLCDRGBI2C.ButtonByName(reply.Button)
+	if !ok {
+		return
+	}
+	event := charLCDRGBI2C.ButtonEvent{Button: button, Pressed: reply.Pressed}
+	if reply.Time != nil {
+		event.Time = *reply.Time
+	}
+
+	for w := range c.watchers {
+		if !reply.Owner && !w.all {
+			continue
+		}
+		select {
+		case w.events <- event:
+		default:
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package daemon_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jyap808/charLCDRGBI2C"
+	"github.com/jyap808/charLCDRGBI2C/daemon"
+	"github.com/jyap808/charLCDRGBI2C/sim"
+)
+
+// serve runs a server for a simulated board and returns its socket
+func serve(t *testing.T) (string, *sim.Device) {
+	t.Helper()
+	return serveWith(t, func(*daemon.Server) {})
+}
+
+// serveWith is serve with the server set up by setup first
+func serveWith(t *testing.T, setup func(*daemon.Server)) (string, *sim.Device) {
+	t.Helper()
+	lcd, dev := sim.NewLCD(t, charLCDRGBI2C.Geometry16x2, sim.Wiring{})
+
+	path := filepath.Join(t.TempDir(), "lcdd.sock")
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	server := daemon.NewServer(lcd)
+	setup(server)
+	go func() {
+		result <- server.ListenAndServe(ctx, path)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-result; !errors.Is(err, context.Canceled) {
+			t.Errorf("ListenAndServe: %v", err)
+		}
+	})
+
+	// Wait for the socket
+	for i := 0; ; i++ {
+		c, err := daemon.Dial(path)
+		if err == nil {
+			c.Close()
+			return path, dev
+		}
+		if i == 100 {
+			t.Fatal(err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestMessage(t *testing.T) {
+	path, dev := serve(t)
+	c, err := daemon.Dial(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	c.CursorPosition(2, 1)
+	c.Message("Hello")
+	if err := c.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if got := string(dev.Codes()[1]); got != "  Hello         " {
+		t.Errorf("line 1 is %q, want %q", got, "  Hello         ")
+	}
+}
+
+func TestWatchEndsWithConnection(t *testing.T) {
+	path, _ := serve(t)
+	c, err := daemon.Dial(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events := c.WatchButtons(context.Background(), 0)
+	c.Close()
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Error("got an event, want the channel closed")
+		}
+	case <-time.After(time.Second):
+		t.Error("events not closed with the connection")
+	}
+}
+
+func TestInvalidGlyph(t *testing.T) {
+	path, _ := serve(t)
+	for _, pattern := range [][]byte{nil, {0x20}, make([]byte, 12)} {
+		c, err := daemon.Dial(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.CreateChar(0, pattern)
+		if c.Err() == nil {
+			t.Errorf("pattern %v accepted", pattern)
+		}
+		c.Close()
+	}
+}
+
+func TestSocketMode(t *testing.T) {
+	for _, mode := range []os.FileMode{0, 0o660} {
+		path, _ := serveWith(t, func(s *daemon.Server) { s.SocketMode = mode })
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := mode
+		if want == 0 {
+			want = 0o600
+		}
+		if got := info.Mode().Perm(); got != want {
+			t.Errorf("SocketMode %v: socket mode %v, want %v", mode, got, want)
+		}
+	}
+}
+
+// TestClaim checks the screen of the client with the highest priority is
+// shown, and the others' when it goes
+func TestClaim(t *testing.T) {
+	path, dev := serve(t)
+	dial := func(text string) *daemon.Client {
+		c, err := daemon.Dial(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.Message(text)
+		if err := c.Err(); err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	line := func() string { return string(dev.Codes()[0]) }
+
+	dashboard := dial("dashboard")
+	defer dashboard.Close()
+	alert := dial("alert")
+	if err := dashboard.Claim(1); err != nil {
+		t.Fatal(err)
+	}
+	if got := line(); got != "dashboard       " {
+		t.Errorf("line 0 is %q with the dashboard claimed", got)
+	}
+	if err := alert.Claim(2); err != nil {
+		t.Fatal(err)
+	}
+	if got := line(); got != "alert           " {
+		t.Errorf("line 0 is %q with the alert claimed", got)
+	}
+	if owner, err := dashboard.Owner(); err != nil || owner {
+		t.Errorf("dashboard owner %v %v, want false", owner, err)
+	}
+
+	alert.Close()
+	deadline := time.Now().Add(5 * time.Second)
+	for line() != "dashboard       " {
+		if time.Now().After(deadline) {
+			t.Fatalf("line 0 is %q after the alert went", line())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,76 @@
+// Package daemon shares one LCD between processes. A Server owns the
+// display and serves a JSON protocol on a Unix domain socket, a Client
+// speaks it and implements charLCDRGBI2C.Panel so applications can use a
+// local or a shared display alike.
+//
+// Every line sent to the daemon is a JSON Request and every line sent back
+// is a JSON Reply, either the answer to a request with the same id or a
+// button event:
+//
+//	{"id":1,"op":"write","row":1,"column":0,"text":"Hello"}
+//	{"id":1}
+//	{"event":"button","button":"select","pressed":true,"owner":true,"time":"..."}
+//
+// The ops are:
+//
+//	size                         reply with the columns and lines
+//	clear                        clear the screen
+//	position row column          move the cursor
+//	write row column text        write text like Message from row, column
+//	cursor on / blink on         show the cursor, blink it
+//	display on                   turn the display on or off
+//	color color                  set the RGB LED, [red, green, blue] from 0-100
+//	backlight on                 turn the backlight on or off
+//	glyph location pattern       define a custom character
+//	state                        reply with the text, color, backlight and owner
+//	claim priority               claim the screen with a priority
+//	release                      give up the screen
+//	subscribe [all]              send button events, all of them or only
+//	                             those while owning the screen
+//
+// Each client draws on its own virtual screen, which is shown while the
+// client owns the display. The owner is the client with the highest
+// claimed priority, the newest claim winning between equal priorities.
+// Drawing claims priority 0 if the client has not claimed yet.
+package daemon
+
+import "time"
+
+// Request is a line sent to the daemon
+type Request struct {
+	ID       int     `json:"id"`
+	Op       string  `json:"op"`
+	Row      int     `json:"row,omitempty"`
+	Column   int     `json:"column,omitempty"`
+	Text     string  `json:"text,omitempty"`
+	On       bool    `json:"on,omitempty"`
+	Color    *[3]int `json:"color,omitempty"`
+	Location byte    `json:"location,omitempty"`
+	Pattern  []int   `json:"pattern,omitempty"`
+	Priority int     `json:"priority,omitempty"`
+	All      bool    `json:"all,omitempty"`
+}
+
+// Reply is a line sent by the daemon. Replies carry the id of their
+// request, button events have an empty id and Event set to "button".
+type Reply struct {
+	ID    int    `json:"id,omitempty"`
+	Error string `json:"error,omitempty"`
+
+	// Replies to size and state
+	Columns   int      `json:"columns,omitempty"`
+	Lines     int      `json:"lines,omitempty"`
+	Text      []string `json:"text,omitempty"`
+	Color     *[3]int  `json:"color,omitempty"`
+	Backlight bool     `json:"backlight,omitempty"`
+
+	// Owner is set when the client owns the screen, in state replies and
+	// button events
+	Owner bool `json:"owner,omitempty"`
+
+	// Button events
+	Event   string     `json:"event,omitempty"`
+	Button  string     `json:"button,omitempty"` // Button name, e.g. "select"
+	Pressed bool       `json:"pressed,omitempty"`
+	Time    *time.Time `json:"time,omitempty"`
+}
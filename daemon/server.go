@@ -0,0 +1,469 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jyap808/charLCDRGBI2C"
+)
+
+// Server owns a panel and shares it between the clients of a socket
+type Server struct {
+	ButtonInterval time.Duration // Button polling interval, zero means 20ms
+	SocketMode     os.FileMode   // Permissions of the socket ListenAndServe creates, zero means 0600
+
+	panel   charLCDRGBI2C.Panel
+	columns int
+	lines   int
+
+	// draw runs the requests one at a time and is held across the panel
+	// I/O, mu guards the fields below and is only held briefly so that button
+	// events are not held up by a slow panel. draw is taken before mu.
+	draw    sync.Mutex
+	mu      sync.Mutex
+	clients map[*client]bool
+	owner   *client // Client shown on the display, nil when nobody claimed it
+	claims  int     // Number of claims so far, orders equal priorities
+}
+
+// client is the state of one connection
+type client struct {
+	conn net.Conn
+	out  chan Reply // Replies and events, written by writeLoop
+
+	// Guarded by Server.mu
+	priority   int
+	claim      int // Claim order, zero when the client has not claimed
+	subscribed bool
+	allEvents  bool
+
+	// Virtual screen, guarded by Server.draw
+	cells       [][]byte
+	row, column int
+	cursor      bool
+	blink       bool
+	display     bool
+	color       [3]int
+	glyphs      map[byte][]byte
+}
+
+// NewServer returns a server for panel. The server owns the panel, nothing
+// else should draw on it while the server runs.
+func NewServer(panel charLCDRGBI2C.Panel) *Server {
+	columns, lines := panel.Size()
+	return &Server{
+		panel:   panel,
+		columns: columns,
+		lines:   lines,
+		clients: make(map[*client]bool),
+	}
+}
+
+// ListenAndServe listens on the Unix domain socket path and serves until
+// ctx is done. A stale socket left by a previous run is removed, a socket
+// another server is listening on is an error. Only the users SocketMode
+// allows can connect, by default the one the server runs as.
+func (s *Server) ListenAndServe(ctx context.Context, path string) error {
+	if _, err := os.Stat(path); err == nil {
+		if conn, err := net.Dial("unix", path); err == nil {
+			conn.Close()
+			return fmt.Errorf("daemon already listening on %s", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+	}
+
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(path)
+	mode := s.SocketMode
+	if mode == 0 {
+		mode = 0o600
+	}
+	if err := os.Chmod(path, mode); err != nil {
+		l.Close()
+		return err
+	}
+	return s.Serve(ctx, l)
+}
+
+// Serve accepts connections on l until ctx is done, then closes l and every
+// connection. It returns ctx.Err() after ctx is done, or the accept error.
+func (s *Server) Serve(ctx context.Context, l net.Listener) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		<-ctx.Done()
+		l.Close()
+
+		s.mu.Lock()
+		for c := range s.clients {
+			c.conn.Close()
+		}
+		s.mu.Unlock()
+	}()
+
+	interval := s.ButtonInterval
+	if interval <= 0 {
+		interval = 20 * time.Millisecond
+	}
+	events := s.panel.WatchButtons(ctx, interval)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for event := range events {
+			s.broadcast(event)
+		}
+	}()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.serveConn(conn)
+		}()
+	}
+}
+
+// serveConn reads and answers the requests of one connection
+func (s *Server) serveConn(conn net.Conn) {
+	c := &client{
+		conn:    conn,
+		out:     make(chan Reply, 16),
+		display: true,
+		glyphs:  make(map[byte][]byte),
+	}
+	c.cells = make([][]byte, s.lines)
+	for row := range c.cells {
+		c.cells[row] = []byte(strings.Repeat(" ", s.columns))
+	}
+
+	s.mu.Lock()
+	s.clients[c] = true
+	s.mu.Unlock()
+
+	written := make(chan struct{})
+	go func() {
+		defer close(written)
+		c.writeLoop()
+	}()
+
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		var req Request
+		reply := Reply{}
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			reply.Error = fmt.Sprintf("invalid request: %v", err)
+		} else {
+			reply = s.handle(c, req)
+		}
+		reply.ID = req.ID
+		c.out <- reply
+	}
+
+	// Hand the screen to the next client
+	s.draw.Lock()
+	s.mu.Lock()
+	delete(s.clients, c)
+	if s.owner == c {
+		s.owner = nil
+	}
+	next := s.elect()
+	close(c.out)
+	s.mu.Unlock()
+	s.show(next)
+	s.draw.Unlock()
+
+	<-written
+	conn.Close()
+}
+
+// writeLoop writes replies and events until out is closed
+func (c *client) writeLoop() {
+	enc := json.NewEncoder(c.conn)
+	for reply := range c.out {
+		if err := enc.Encode(reply); err != nil {
+			// Drain so senders never block on a dead connection
+			c.conn.Close()
+		}
+	}
+}
+
+// broadcast sends a button event to the subscribed clients. Events are
+// dropped for clients that are not reading them.
+func (s *Server) broadcast(event charLCDRGBI2C.ButtonEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for c := range s.clients {
+		owner := c == s.owner
+		if !c.subscribed || !owner && !c.allEvents {
+			continue
+		}
+		reply := Reply{
+			Event:   "button",
+			Button:  charLCDRGBI2C.ButtonName(event.Button),
+			Pressed: event.Pressed,
+			Owner:   owner,
+			Time:    &event.Time,
+		}
+		select {
+		case c.out <- reply:
+		default:
+			log.Printf("Dropping button event for a slow client")
+		}
+	}
+}
+
+// handle runs one request
+func (s *Server) handle(c *client, req Request) Reply {
+	s.draw.Lock()
+	defer s.draw.Unlock()
+
+	switch req.Op {
+	case "size":
+		return Reply{Columns: s.columns, Lines: s.lines}
+
+	case "state":
+		text := make([]string, len(c.cells))
+		for row, line := range c.cells {
+			text[row] = codes(line)
+		}
+		color := c.color
+		s.mu.Lock()
+		owner := c == s.owner
+		s.mu.Unlock()
+		return Reply{Text: text, Color: &color, Backlight: s.panel.Backlight(), Owner: owner}
+
+	case "claim":
+		s.mu.Lock()
+		c.priority = req.Priority
+		s.claims++
+		c.claim = s.claims
+		next := s.elect()
+		s.mu.Unlock()
+		s.show(next)
+
+	case "release":
+		s.mu.Lock()
+		c.claim = 0
+		next := s.elect()
+		s.mu.Unlock()
+		s.show(next)
+
+	case "subscribe":
+		s.mu.Lock()
+		c.subscribed = true
+		c.allEvents = c.allEvents || req.All
+		s.mu.Unlock()
+
+	case "backlight":
+		// The backlight is shared by every client
+		if err := s.panel.SetBacklight(req.On); err != nil {
+			return Reply{Error: err.Error()}
+		}
+
+	case "clear", "position", "write", "cursor", "blink", "display", "color", "glyph":
+		if err := s.drawOn(c, req); err != nil {
+			return Reply{Error: err.Error()}
+		}
+
+	default:
+		return Reply{Error: fmt.Sprintf("unknown op %q", req.Op)}
+	}
+	return Reply{}
+}
+
+// drawOn runs a drawing request on the client's virtual screen, and on the
+// display when the client owns it. s.draw must be held.
+func (s *Server) drawOn(c *client, req Request) error {
+	switch req.Op {
+	case "position", "write":
+		if req.Row < 0 || req.Row >= s.lines || req.Column < 0 || req.Column >= s.columns {
+			return fmt.Errorf("position %d,%d outside the %dx%d display", req.Row, req.Column, s.columns, s.lines)
+		}
+	case "color":
+		if req.Color == nil {
+			return errors.New("missing color")
+		}
+	case "glyph":
+		if req.Location > 7 {
+			return fmt.Errorf("invalid glyph location %d", req.Location)
+		}
+		if len(req.Pattern) == 0 || len(req.Pattern) > 11 {
+			return errors.New("pattern needs 8 rows, or 11 with the 5x10 font")
+		}
+		for _, row := range req.Pattern {
+			if row < 0 || row > 0x1F {
+				return errors.New("pattern rows must be from 0-31")
+			}
+		}
+	}
+
+	s.mu.Lock()
+	var next *client
+	if c.claim == 0 {
+		s.claims++
+		c.claim = s.claims
+		next = s.elect()
+	}
+	shown := c == s.owner
+	s.mu.Unlock()
+	s.show(next)
+
+	switch req.Op {
+	case "clear":
+		for _, line := range c.cells {
+			for column := range line {
+				line[column] = ' '
+			}
+		}
+		c.row, c.column = 0, 0
+		if shown {
+			s.panel.Clear()
+		}
+
+	case "position":
+		c.row, c.column = req.Row, req.Column
+		if shown {
+			s.panel.CursorPosition(c.column, c.row)
+		}
+
+	case "write":
+		c.write(req.Row, req.Column, req.Text)
+		if shown {
+			s.panel.CursorPosition(req.Column, req.Row)
+			s.panel.Message(req.Text)
+		}
+
+	case "cursor":
+		c.cursor = req.On
+		if shown {
+			s.panel.SetCursor(c.cursor)
+		}
+
+	case "blink":
+		c.blink = req.On
+		if shown {
+			s.panel.SetBlink(c.blink)
+		}
+
+	case "display":
+		c.display = req.On
+		if shown {
+			s.panel.SetDisplay(c.display)
+		}
+
+	case "color":
+		c.color = *req.Color
+		if shown {
+			s.panel.SetColor(c.color[0], c.color[1], c.color[2])
+		}
+
+	case "glyph":
+		pattern := make([]byte, len(req.Pattern))
+		for i, row := range req.Pattern {
+			pattern[i] = byte(row)
+		}
+		c.glyphs[req.Location] = pattern
+		if shown {
+			s.panel.CreateChar(req.Location, pattern)
+		}
+	}
+	return nil
+}
+
+// write puts text on the virtual screen the way Message puts it on the
+// display: wrapping at the last column and dropping text past the last line
+func (c *client) write(row, column int, text string) {
+	for _, character := range text {
+		if character == '\n' || column >= len(c.cells[row]) {
+			row++
+			if row >= len(c.cells) {
+				return
+			}
+			column = 0
+			if character == '\n' {
+				continue
+			}
+		}
+		c.cells[row][column] = byte(character)
+		column++
+	}
+}
+
+// elect picks the owner of the display and returns it when it changed, for
+// the caller to show once it has released s.mu. s.draw and s.mu must be
+// held.
+func (s *Server) elect() *client {
+	var owner *client
+	for c := range s.clients {
+		if c.claim == 0 {
+			continue
+		}
+		if owner == nil || c.priority > owner.priority ||
+			c.priority == owner.priority && c.claim > owner.claim {
+			owner = c
+		}
+	}
+	if owner == s.owner {
+		return nil
+	}
+	s.owner = owner
+	return owner
+}
+
+// show draws a client's virtual screen on the display, nothing when c is
+// nil. s.draw must be held.
+func (s *Server) show(c *client) {
+	if c == nil {
+		return
+	}
+	for location, pattern := range c.glyphs {
+		s.panel.CreateChar(location, pattern)
+	}
+	for row, line := range c.cells {
+		s.panel.CursorPosition(0, row)
+		s.panel.Message(codes(line))
+	}
+	s.panel.SetColor(c.color[0], c.color[1], c.color[2])
+	s.panel.SetDisplay(c.display)
+	s.panel.SetCursor(c.cursor)
+	s.panel.SetBlink(c.blink)
+	s.panel.CursorPosition(c.column, c.row)
+}
+
+// codes turns HD44780 character codes into a string Message writes back
+func codes(line []byte) string {
+	runes := make([]rune, len(line))
+	for i, code := range line {
+		runes[i] = rune(code)
+	}
+	return string(runes)
+}
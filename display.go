@@ -0,0 +1,60 @@
+package charLCDRGBI2C
+
+import (
+	"context"
+	"time"
+)
+
+// Display is the drawing interface of a character LCD with an RGB LED.
+// CharLCDRGBI2C implements it, widgets and screens draw through it so that
+// they work with any implementation.
+type Display interface {
+	Size() (columns, lines int)
+	Clear()
+	CursorPosition(column, row int)
+	Message(message string)
+	CreateChar(location byte, pattern []byte)
+	SetCursor(show bool)
+	SetBlink(blink bool)
+	SetColor(red, green, blue int)
+	SetBacklight(on bool) error
+	SetDisplay(enable bool)
+	Text() []string
+	Color() (red, green, blue int)
+	Backlight() bool
+}
+
+// Panel is a Display with the on-board buttons. CharLCDRGBI2C implements it
+// locally and remote clients implement it over the network, so code can
+// switch between a local and a remote panel.
+type Panel interface {
+	Display
+	WatchButtons(ctx context.Context, interval time.Duration) <-chan ButtonEvent
+}
+
+var _ Panel = (*CharLCDRGBI2C)(nil)
+
+// Size returns the number of columns and lines of the LCD
+func (lcd *CharLCDRGBI2C) Size() (columns, lines int) {
+	return lcd.columns, lcd.lines
+}
+
+// Text returns the characters shown on each line. Every rune is an HD44780
+// character code, so lines can be written back with Message.
+func (lcd *CharLCDRGBI2C) Text() []string {
+	lcd.lock()
+	defer lcd.unlock()
+
+	text := make([]string, len(lcd.fb.cells))
+	for row, line := range lcd.fb.cells {
+		text[row] = codes(line)
+	}
+	return text
+}
+
+// Color returns the RGB LED color (values from 0-100)
+func (lcd *CharLCDRGBI2C) Color() (red, green, blue int) {
+	lcd.lock()
+	defer lcd.unlock()
+	return lcd.colorValue[0], lcd.colorValue[1], lcd.colorValue[2]
+}
@@ -0,0 +1,146 @@
+package charLCDRGBI2C
+
+import (
+	"fmt"
+
+	"github.com/googolgl/go-i2c"
+	"github.com/googolgl/go-mcp23017"
+)
+
+// PinDriver sets and reads the I/O expander pins the LCD, LED and buttons
+// are wired to, named "A0" to "B7". New drives an MCP23017, NewWithDriver
+// accepts any implementation such as a simulator.
+type PinDriver interface {
+	Output(pins ...string) error
+	Input(pins ...string) error
+	High(pins ...string) error
+	Low(pins ...string) error
+	PullUp(pins ...string) error
+	PullDown(pins ...string) error
+	Read(pins ...string) (map[string]uint8, error)
+}
+
+// DirectionReader is a PinDriver that can read back which pins are outputs,
+// telling a board that is already set up from one that was reset. Wrappers
+// return errors.ErrUnsupported when the driver they wrap can not.
+type DirectionReader interface {
+	Outputs(pins ...string) (map[string]bool, error)
+}
+
+// MCP23017Driver is the PinDriver of an MCP23017 I/O expander
+type MCP23017Driver struct {
+	mcp *mcp23017.MCP23017
+	i2c *i2c.Options
+}
+
+// Resetter is a PinDriver whose expander can be set up from scratch after
+// it lost its configuration, e.g. in a power cycle
+type Resetter interface {
+	Reset() error
+}
+
+var (
+	_ DirectionReader = (*MCP23017Driver)(nil)
+	_ Resetter        = (*MCP23017Driver)(nil)
+)
+
+// MCP23017 registers with IOCON.BANK=1, which the mcp23017 package runs the
+// chip with. Port B registers are 0x10 above port A.
+const (
+	bank1IODIRB = IODIRA | 0x10
+	bank1IOCON  = 0x05
+	ioconBank   = 0x80
+)
+
+// NewMCP23017Driver sets up the MCP23017 on an I2C device. Pin directions
+// set by a previous process are kept, so that the LCD keeps its contents.
+func NewMCP23017Driver(i2c *i2c.Options) (*MCP23017Driver, error) {
+	// mcp23017.New makes every pin an input
+	iodir, configured := readIODIR(i2c)
+
+	mcp, err := mcp23017.New(i2c)
+	if err != nil {
+		return nil, err
+	}
+	if configured {
+		if err := i2c.WriteRegU8(IODIRA, iodir[0]); err != nil {
+			return nil, err
+		}
+		if err := i2c.WriteRegU8(bank1IODIRB, iodir[1]); err != nil {
+			return nil, err
+		}
+	}
+	return &MCP23017Driver{mcp: mcp, i2c: i2c}, nil
+}
+
+// Reset sets the MCP23017 up again, every pin becomes an input
+func (d *MCP23017Driver) Reset() error {
+	mcp, err := mcp23017.New(d.i2c)
+	if err != nil {
+		return err
+	}
+	d.mcp = mcp
+	return nil
+}
+
+func (d *MCP23017Driver) Output(pins ...string) error   { return d.mcp.Set(pins).OUTPUT() }
+func (d *MCP23017Driver) Input(pins ...string) error    { return d.mcp.Set(pins).INPUT() }
+func (d *MCP23017Driver) High(pins ...string) error     { return d.mcp.Set(pins).HIGH() }
+func (d *MCP23017Driver) Low(pins ...string) error      { return d.mcp.Set(pins).LOW() }
+func (d *MCP23017Driver) PullUp(pins ...string) error   { return d.mcp.Set(pins).PULLUP() }
+func (d *MCP23017Driver) PullDown(pins ...string) error { return d.mcp.Set(pins).PULLDOWN() }
+
+func (d *MCP23017Driver) Read(pins ...string) (map[string]uint8, error) {
+	return d.mcp.Get(pins)
+}
+
+// Outputs reads back the IODIR registers and reports which pins are outputs.
+// Every pin is an input on a chip that was reset since it was set up.
+func (d *MCP23017Driver) Outputs(pins ...string) (map[string]bool, error) {
+	iodir, err := readBank1IODIR(d.i2c)
+	if err != nil {
+		return nil, err
+	}
+
+	outputs := make(map[string]bool)
+	for _, pin := range pins {
+		if len(pin) != 2 || (pin[0] != 'A' && pin[0] != 'B') || pin[1] < '0' || pin[1] > '7' {
+			return nil, fmt.Errorf("invalid pin %q", pin)
+		}
+		// A clear IODIR bit makes the pin an output
+		outputs[pin] = iodir[pin[0]-'A']&(1<<(pin[1]-'0')) == 0
+	}
+	return outputs, nil
+}
+
+// readIODIR reads the IODIR registers of a chip set up by the mcp23017
+// package, reporting false when it is not or does not respond
+func readIODIR(i2c *i2c.Options) ([2]byte, bool) {
+	iodir, err := readBank1IODIR(i2c)
+	return iodir, err == nil && iodir != [2]byte{0xFF, 0xFF}
+}
+
+// readBank1IODIR reads the IODIR registers of ports A and B. A chip that is
+// not in BANK=1 mode has been reset, its pins are all inputs.
+func readBank1IODIR(i2c *i2c.Options) ([2]byte, error) {
+	iodir := [2]byte{0xFF, 0xFF}
+
+	// Reset chips run with BANK=0, which has GPINTENB at this address. It
+	// is cleared at reset and by the mcp23017 package.
+	iocon, err := i2c.ReadRegU8(bank1IOCON)
+	if err != nil {
+		return iodir, err
+	}
+	if iocon&ioconBank == 0 {
+		return iodir, nil
+	}
+
+	for port, register := range []byte{IODIRA, bank1IODIRB} {
+		value, err := i2c.ReadRegU8(register)
+		if err != nil {
+			return iodir, err
+		}
+		iodir[port] = value
+	}
+	return iodir, nil
+}
@@ -0,0 +1,421 @@
+// Package emulator shows a simulated board in a terminal, for developing
+// without the hardware. The panel is drawn with its backlight color, the
+// arrow keys work the direction buttons and Enter or space the select
+// button. An Emulator is a charLCDRGBI2C.PinDriver, so an application only
+// picks the driver at startup:
+//
+//	emu, err := emulator.Open(charLCDRGBI2C.Geometry16x2, sim.Wiring{})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer emu.Close()
+//	log.SetOutput(emu) // Log lines show below the panel
+//	lcd, err := charLCDRGBI2C.NewWithDriver(emu, charLCDRGBI2C.Geometry16x2)
+//
+// Ctrl-C interrupts the process like it does outside the emulator.
+package emulator
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jyap808/charLCDRGBI2C"
+	"github.com/jyap808/charLCDRGBI2C/sim"
+	"golang.org/x/term"
+)
+
+// Emulator is a simulated board drawn in a terminal
+type Emulator struct {
+	*sim.Device
+
+	refresh  time.Duration // How often the panel is redrawn when it changed
+	holdTime time.Duration // How long a key holds its button down
+
+	in  io.Reader
+	out io.Writer
+
+	mu       sync.Mutex
+	frame    string                 // Last frame drawn
+	logs     []string               // Last log lines, see Write
+	partial  []byte                 // Log output after the last newline
+	releases map[string]*time.Timer // Pending button releases
+	restore  func() error           // Puts the terminal back, set by Open
+
+	done chan struct{}
+	wg   sync.WaitGroup
+	once sync.Once
+}
+
+var _ charLCDRGBI2C.PinDriver = (*Emulator)(nil)
+
+// logLines is how many log lines show below the panel
+const logLines = 5
+
+// escapeTimeout is how long the rest of an escape sequence is waited for
+// before ESC counts as a key of its own
+const escapeTimeout = 50 * time.Millisecond
+
+// Option configures an Emulator
+type Option func(*Emulator)
+
+// WithRefresh sets how often the panel is redrawn when it changed, the
+// default is 50ms
+func WithRefresh(d time.Duration) Option {
+	return func(e *Emulator) {
+		e.refresh = d
+	}
+}
+
+// WithHoldTime sets how long a key holds its button down, the default is
+// 200ms
+func WithHoldTime(d time.Duration) Option {
+	return func(e *Emulator) {
+		e.holdTime = d
+	}
+}
+
+// Open shows a board in the terminal on standard input and output,
+// switching it to raw mode so single keys are read. Close puts the
+// terminal back.
+func Open(geometry charLCDRGBI2C.Geometry, wiring sim.Wiring, opts ...Option) (*Emulator, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return nil, fmt.Errorf("emulator: standard input is not a terminal")
+	}
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, err
+	}
+
+	e := New(geometry, wiring, os.Stdin, os.Stdout, opts...)
+	e.restore = func() error {
+		return term.Restore(fd, state)
+	}
+	return e, nil
+}
+
+// New shows a board on out, reading keys from in. The caller puts the
+// terminal in raw mode.
+func New(geometry charLCDRGBI2C.Geometry, wiring sim.Wiring, in io.Reader, out io.Writer, opts ...Option) *Emulator {
+	e := &Emulator{
+		Device:   sim.New(geometry, wiring),
+		refresh:  50 * time.Millisecond,
+		holdTime: 200 * time.Millisecond,
+		in:       in,
+		out:      out,
+		releases: make(map[string]*time.Timer),
+		done:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	// Switch to the alternate screen and hide the terminal cursor
+	fmt.Fprint(out, "\x1b[?1049h\x1b[?25l")
+
+	e.wg.Add(1)
+	go e.drawLoop()
+	go e.readKeys()
+	return e
+}
+
+// Close stops drawing and puts the terminal back. The keys goroutine ends
+// with the next key, or when in is closed.
+func (e *Emulator) Close() error {
+	var err error
+	e.once.Do(func() {
+		close(e.done)
+		e.wg.Wait()
+
+		e.mu.Lock()
+		for _, timer := range e.releases {
+			timer.Stop()
+		}
+		fmt.Fprint(e.out, "\x1b[?25h\x1b[?1049l")
+		// What was logged stays readable after the alternate screen is gone
+		for _, line := range e.logs {
+			fmt.Fprint(e.out, line+"\r\n")
+		}
+		e.mu.Unlock()
+
+		if e.restore != nil {
+			err = e.restore()
+		}
+	})
+	return err
+}
+
+// Write takes log output and shows its last lines below the panel, pass
+// the Emulator to log.SetOutput
+func (e *Emulator) Write(p []byte) (int, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.partial = append(e.partial, p...)
+	for {
+		i := bytes.IndexByte(e.partial, '\n')
+		if i < 0 {
+			break
+		}
+		e.logs = append(e.logs, string(e.partial[:i]))
+		e.partial = e.partial[i+1:]
+	}
+	if len(e.logs) > logLines {
+		e.logs = e.logs[len(e.logs)-logLines:]
+	}
+	e.frame = "" // Draw the new lines
+	return len(p), nil
+}
+
+// drawLoop redraws the panel whenever it changed
+func (e *Emulator) drawLoop() {
+	defer e.wg.Done()
+
+	ticker := time.NewTicker(e.refresh)
+	defer ticker.Stop()
+
+	for {
+		e.draw()
+		select {
+		case <-e.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// draw writes a frame when it differs from the last one
+func (e *Emulator) draw() {
+	panel := e.panel()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var b strings.Builder
+	b.WriteString(panel)
+	b.WriteString("\r\n\x1b[2m←↑↓→ buttons, Enter select, Ctrl-C quit\x1b[0m\r\n\r\n")
+	for _, line := range e.logs {
+		b.WriteString(line)
+		b.WriteString("\x1b[K\r\n")
+	}
+	frame := b.String()
+	if frame == e.frame {
+		return
+	}
+	e.frame = frame
+	fmt.Fprint(e.out, "\x1b[H\x1b[2J"+frame)
+}
+
+// panel draws the panel with ANSI colors
+func (e *Emulator) panel() string {
+	codes := e.Codes()
+	column, row, blink, cursor := e.Cursor()
+	underline := cursor && e.Underline()
+	background := e.background()
+
+	var b strings.Builder
+	border := "+" + strings.Repeat("-", len(codes[0])+2) + "+\r\n"
+	b.WriteString(border)
+	for r, line := range codes {
+		b.WriteString("|" + background + "\x1b[38;2;16;16;16m ")
+		for c, code := range line {
+			here := cursor && r == row && c == column
+			var style []string
+			if here && underline {
+				style = append(style, "4")
+			}
+			if here && blink {
+				style = append(style, "5")
+			}
+			if code < 0x10 {
+				// Custom characters show as their location
+				style = append(style, "7")
+			}
+
+			if style == nil {
+				b.WriteString(glyphRune(code))
+				continue
+			}
+			b.WriteString("\x1b[" + strings.Join(style, ";") + "m")
+			b.WriteString(glyphRune(code))
+			b.WriteString("\x1b[24;25;27m")
+		}
+		b.WriteString(" \x1b[0m|\r\n")
+	}
+	b.WriteString(border)
+	return b.String()
+}
+
+// background returns the ANSI background of the lit or unlit panel,
+// tinted by the LED
+func (e *Emulator) background() string {
+	red, green, blue := e.LED()
+	if !e.Backlight() && !red && !green && !blue {
+		return "\x1b[48;2;90;96;88m"
+	}
+	if !red && !green && !blue {
+		return "\x1b[48;2;230;230;230m"
+	}
+	level := func(on bool) int {
+		if on {
+			return 230
+		}
+		return 60
+	}
+	return fmt.Sprintf("\x1b[48;2;%d;%d;%dm", level(red), level(green), level(blue))
+}
+
+// romRunes are the characters of the A00 ROM that differ from ASCII or lie
+// above it
+var romRunes = map[byte]string{
+	0x5C: "¥", 0x7E: "→", 0x7F: "←", 0xA5: "・", 0xB0: "ー",
+	0xDF: "°", 0xE0: "α", 0xE4: "µ", 0xF4: "Ω", 0xFD: "÷", 0xFF: "█",
+}
+
+// glyphRune returns what a character code shows as in the terminal
+func glyphRune(code byte) string {
+	switch {
+	case code < 0x10:
+		return string(rune('0' + code&0x7))
+	case romRunes[code] != "":
+		return romRunes[code]
+	case code < 0x20 || (code >= 0x80 && code < 0xA1):
+		return " "
+	case code > 0x7F:
+		return "?"
+	}
+	return string(rune(code))
+}
+
+// readKeys presses buttons for the keys read from in
+func (e *Emulator) readKeys() {
+	input := make(chan []byte)
+	go func() {
+		defer close(input)
+		buf := make([]byte, 64)
+		for {
+			n, err := e.in.Read(buf)
+			if n > 0 {
+				select {
+				case input <- bytes.Clone(buf[:n]):
+				case <-e.done:
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	var pending []byte
+	var timeout <-chan time.Time
+	for {
+		select {
+		case <-e.done:
+			return
+		case data, ok := <-input:
+			if !ok {
+				return
+			}
+			pending = append(pending, data...)
+		case <-timeout:
+			// The rest never came, the ESC was pressed on its own
+			pending = pending[1:]
+		}
+
+		timeout = nil
+		for len(pending) > 0 {
+			button, size := parseKey(pending)
+			if size == 0 {
+				// Wait a moment for the rest of an escape sequence
+				timeout = time.After(escapeTimeout)
+				break
+			}
+			pending = pending[size:]
+
+			switch button {
+			case "":
+			case interrupt:
+				interruptProcess()
+			default:
+				e.tap(button)
+			}
+		}
+	}
+}
+
+// interrupt is what parseKey returns for Ctrl-C
+const interrupt = "interrupt"
+
+// parseKey decodes the key at the start of input, returning the button it
+// works and how many bytes it took, zero when the key is incomplete
+func parseKey(input []byte) (button string, size int) {
+	switch input[0] {
+	case 3:
+		return interrupt, 1
+	case '\r', '\n', ' ':
+		return charLCDRGBI2C.SelectButton, 1
+	case 0x1b:
+	default:
+		return "", 1
+	}
+
+	// Arrows are ESC [ A or, in application mode, ESC O A
+	if len(input) < 3 {
+		if len(input) == 2 && input[1] != '[' && input[1] != 'O' {
+			return "", 1
+		}
+		return "", 0
+	}
+	if input[1] != '[' && input[1] != 'O' {
+		return "", 1
+	}
+	switch input[2] {
+	case 'A':
+		return charLCDRGBI2C.UpButton, 3
+	case 'B':
+		return charLCDRGBI2C.DownButton, 3
+	case 'C':
+		return charLCDRGBI2C.RightButton, 3
+	case 'D':
+		return charLCDRGBI2C.LeftButton, 3
+	}
+	// Skip other sequences up to their final byte
+	for i := 2; i < len(input); i++ {
+		if input[i] >= 0x40 && input[i] <= 0x7E {
+			return "", i + 1
+		}
+	}
+	return "", 0
+}
+
+// tap holds a button down for the hold time. Terminals do not report key
+// releases, the key repeat of a held key keeps the button down.
+func (e *Emulator) tap(button string) {
+	hold := e.holdTime
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if timer, ok := e.releases[button]; ok && timer.Stop() {
+		timer.Reset(hold)
+		return
+	}
+	e.Press(button)
+	e.releases[button] = time.AfterFunc(hold, func() {
+		e.Release(button)
+	})
+}
+
+// interruptProcess sends the process the interrupt Ctrl-C sends outside
+// raw mode
+func interruptProcess() {
+	if p, err := os.FindProcess(os.Getpid()); err == nil {
+		p.Signal(os.Interrupt)
+	}
+}
@@ -0,0 +1,60 @@
+package emulator_test
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/jyap808/charLCDRGBI2C"
+	"github.com/jyap808/charLCDRGBI2C/emulator"
+	"github.com/jyap808/charLCDRGBI2C/sim"
+)
+
+// newEmulator returns an LCD on an emulator and the writer its keys come from
+func newEmulator(t *testing.T) (*charLCDRGBI2C.CharLCDRGBI2C, io.Writer) {
+	t.Helper()
+	keys, in := io.Pipe()
+	e := emulator.New(charLCDRGBI2C.Geometry16x2, sim.Wiring{}, keys, io.Discard,
+		emulator.WithRefresh(time.Millisecond), emulator.WithHoldTime(time.Minute))
+	t.Cleanup(func() {
+		e.Close()
+		in.Close()
+	})
+	lcd, err := charLCDRGBI2C.NewWithDriver(e, charLCDRGBI2C.Geometry16x2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return lcd, in
+}
+
+// awaitPressed waits for button to be pressed
+func awaitPressed(t *testing.T, lcd *charLCDRGBI2C.CharLCDRGBI2C, button string) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !lcd.IsButtonPressed(button) {
+		if time.Now().After(deadline) {
+			t.Fatalf("%s button not pressed", charLCDRGBI2C.ButtonName(button))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestArrowKeys(t *testing.T) {
+	lcd, in := newEmulator(t)
+
+	// A sequence split over two reads
+	in.Write([]byte("\x1b["))
+	in.Write([]byte("A"))
+	awaitPressed(t, lcd, charLCDRGBI2C.UpButton)
+}
+
+func TestLoneEscape(t *testing.T) {
+	lcd, in := newEmulator(t)
+
+	// ESC pressed on its own, then [ and Enter, must not swallow Enter
+	in.Write([]byte("\x1b"))
+	time.Sleep(200 * time.Millisecond)
+	in.Write([]byte("["))
+	in.Write([]byte("\r"))
+	awaitPressed(t, lcd, charLCDRGBI2C.SelectButton)
+}
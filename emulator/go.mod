@@ -0,0 +1,15 @@
+module github.com/jyap808/charLCDRGBI2C/emulator
+
+go 1.23.6
+
+require (
+	github.com/jyap808/charLCDRGBI2C v0.1.0
+	golang.org/x/term v0.32.0
+)
+
+require (
+	github.com/googolgl/go-i2c v0.1.1 // indirect
+	github.com/googolgl/go-mcp23017 v0.0.0-20210225115400-eb8b77d91034 // indirect
+	github.com/sirupsen/logrus v1.8.1 // indirect
+	golang.org/x/sys v0.33.0 // indirect
+)
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/googolgl/go-i2c"
+	"github.com/googolgl/go-mcp23017"
+	"github.com/jyap808/charLCDRGBI2C"
+	"github.com/jyap808/charLCDRGBI2C/emulator"
+	"github.com/jyap808/charLCDRGBI2C/sim"
+	"github.com/jyap808/charLCDRGBI2C/ui"
+)
+
+// Run it on the board, or anywhere with:
+//
+//	go run ./examples/emulator -emulate
+var emulate = flag.Bool("emulate", false, "show the LCD in the terminal instead of using the board")
+
+func main() {
+	flag.Parse()
+	geometry := charLCDRGBI2C.Geometry16x2
+
+	// The same program drives the board or the emulator
+	var driver charLCDRGBI2C.PinDriver
+	if *emulate {
+		emu, err := emulator.Open(geometry, sim.Wiring{})
+		if err != nil {
+			log.Fatalf("Failed to start the emulator: %v", err)
+		}
+		defer emu.Close()
+		log.SetOutput(emu)
+		driver = emu
+	} else {
+		i2c, err := i2c.New(mcp23017.DefI2CAdr, "/dev/i2c-1")
+		if err != nil {
+			log.Fatalf("Failed to initialize I2C: %v", err)
+		}
+		defer i2c.Close()
+		driver, err = charLCDRGBI2C.NewMCP23017Driver(i2c)
+		if err != nil {
+			log.Fatalf("Failed to initialize MCP23017: %v", err)
+		}
+	}
+
+	lcd, err := charLCDRGBI2C.NewWithDriver(driver, geometry)
+	if err != nil {
+		log.Printf("Failed to initialize LCD: %v", err)
+		return
+	}
+	defer lcd.Close(charLCDRGBI2C.CloseOptions{Clear: true, LEDOff: true})
+	lcd.SetBacklight(true)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	events := lcd.WatchButtons(ctx, 20*time.Millisecond)
+
+	colors := [][3]int{{0, 0, 0}, {100, 0, 0}, {0, 100, 0}, {0, 0, 100}}
+	menu := &ui.Menu{
+		Title: "Main",
+		Items: []ui.Item{
+			&ui.Choice{
+				Name:    "LED",
+				Options: []string{"Off", "Red", "Green", "Blue"},
+				Changed: func(index int) {
+					lcd.SetColor(colors[index][0], colors[index][1], colors[index][2])
+				},
+			},
+			&ui.Toggle{Name: "Cursor", Changed: lcd.SetCursor},
+			&ui.Action{Name: "Quit"},
+		},
+	}
+
+	for {
+		item, err := menu.Run(ctx, lcd, events)
+		if err != nil {
+			log.Printf("Menu closed: %v", err)
+			return
+		}
+		log.Printf("Selected: %s", item.Label())
+		if item.Label() == "Quit" {
+			return
+		}
+	}
+}
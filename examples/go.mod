@@ -0,0 +1,21 @@
+module github.com/jyap808/charLCDRGBI2C/examples
+
+go 1.23.6
+
+require (
+	github.com/eclipse/paho.mqtt.golang v1.5.0
+	github.com/googolgl/go-i2c v0.1.1
+	github.com/googolgl/go-mcp23017 v0.0.0-20210225115400-eb8b77d91034
+	github.com/jyap808/charLCDRGBI2C v0.1.0
+	github.com/jyap808/charLCDRGBI2C/emulator v0.1.0
+	github.com/jyap808/charLCDRGBI2C/mqttbridge v0.1.0
+)
+
+require (
+	github.com/gorilla/websocket v1.5.3 // indirect
+	github.com/sirupsen/logrus v1.8.1 // indirect
+	golang.org/x/net v0.41.0 // indirect
+	golang.org/x/sync v0.15.0 // indirect
+	golang.org/x/sys v0.33.0 // indirect
+	golang.org/x/term v0.32.0 // indirect
+)
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/googolgl/go-i2c"
+	"github.com/googolgl/go-mcp23017"
+	"github.com/jyap808/charLCDRGBI2C"
+	"github.com/jyap808/charLCDRGBI2C/httpapi"
+)
+
+func main() {
+	// Initialize I2C
+	i2c, err := i2c.New(mcp23017.DefI2CAdr, "/dev/i2c-1")
+	if err != nil {
+		log.Fatalf("Failed to initialize I2C: %v", err)
+	}
+	defer i2c.Close()
+
+	// Create LCD object (16 columns, 2 rows)
+	lcd, err := charLCDRGBI2C.New(i2c, 16, 2)
+	if err != nil {
+		log.Fatalf("Failed to initialize LCD: %v", err)
+	}
+	defer lcd.Close(charLCDRGBI2C.CloseOptions{Clear: true, LEDOff: true})
+	lcd.SetBacklight(true)
+
+	// Try it with:
+	//   curl -X PUT -d '{"text":"Hello"}' localhost:8080/lcd/lines/0
+	//   curl -X PUT -d '{"hex":"#00FF00"}' localhost:8080/lcd/color
+	//   curl -N localhost:8080/lcd/buttons
+	http.Handle("/lcd/", http.StripPrefix("/lcd", httpapi.NewHandler(lcd)))
+	log.Println("Serving the LCD on http://localhost:8080/lcd/")
+	log.Fatal(http.ListenAndServe(":8080", nil))
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/googolgl/go-i2c"
+	"github.com/googolgl/go-mcp23017"
+	"github.com/jyap808/charLCDRGBI2C"
+	"github.com/jyap808/charLCDRGBI2C/ui"
+)
+
+func main() {
+	// Initialize I2C
+	i2c, err := i2c.New(mcp23017.DefI2CAdr, "/dev/i2c-1")
+	if err != nil {
+		log.Fatalf("Failed to initialize I2C: %v", err)
+	}
+	defer i2c.Close()
+
+	// Create LCD object (16 columns, 2 rows)
+	lcd, err := charLCDRGBI2C.New(i2c, 16, 2)
+	if err != nil {
+		log.Fatalf("Failed to initialize LCD: %v", err)
+	}
+	defer lcd.Close(charLCDRGBI2C.CloseOptions{Clear: true, LEDOff: true})
+
+	Menu(lcd)
+}
+
+func Menu(lcd *charLCDRGBI2C.CharLCDRGBI2C) {
+	log.Println("Starting Menu Demo")
+
+	ctx := context.Background()
+	events := lcd.WatchButtons(ctx, 20*time.Millisecond)
+
+	colors := [][3]int{{0, 0, 0}, {100, 0, 0}, {0, 100, 0}, {0, 0, 100}}
+	menu := &ui.Menu{
+		Title: "Main",
+		Items: []ui.Item{
+			&ui.Menu{
+				Title: "Settings",
+				Items: []ui.Item{
+					&ui.Toggle{Name: "Cursor", Changed: lcd.SetCursor},
+					&ui.Choice{
+						Name:    "LED",
+						Options: []string{"Off", "Red", "Green", "Blue"},
+						Changed: func(index int) {
+							lcd.SetColor(colors[index][0], colors[index][1], colors[index][2])
+						},
+					},
+					&ui.Spinner{Name: "Volume", Value: 5, Min: 0, Max: 10},
+				},
+			},
+			&ui.Action{Name: "Say hello"},
+			&ui.Action{Name: "Quit"},
+		},
+	}
+
+	for {
+		item, err := menu.Run(ctx, lcd, events)
+		if err != nil {
+			log.Printf("Menu closed: %v", err)
+			return
+		}
+		log.Printf("Selected: %s", item.Label())
+		if item.Label() == "Quit" {
+			return
+		}
+	}
+}
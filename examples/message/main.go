@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"time"
 
@@ -22,6 +23,7 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to initialize LCD: %v", err)
 	}
+	defer lcd.Close(charLCDRGBI2C.CloseOptions{Clear: true, LEDOff: true, BacklightOff: true})
 
 	Message(lcd)
 }
@@ -48,9 +50,7 @@ func Message(lcd *charLCDRGBI2C.CharLCDRGBI2C) {
 	lcd.Clear()
 	lcd.Message("Scrolling text")
 	time.Sleep(1 * time.Second)
-	for i := 0; i < 5; i++ {
-		lcd.MoveLeft()
-		time.Sleep(500 * time.Millisecond)
+	if err := lcd.ScrollText(context.Background(), 5, 500*time.Millisecond); err != nil {
+		log.Printf("Error scrolling text: %v", err)
 	}
-	lcd.Clear()
 }
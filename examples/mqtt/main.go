@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/googolgl/go-i2c"
+	"github.com/googolgl/go-mcp23017"
+	"github.com/jyap808/charLCDRGBI2C"
+	"github.com/jyap808/charLCDRGBI2C/mqttbridge"
+)
+
+func main() {
+	// Initialize I2C
+	i2c, err := i2c.New(mcp23017.DefI2CAdr, "/dev/i2c-1")
+	if err != nil {
+		log.Fatalf("Failed to initialize I2C: %v", err)
+	}
+	defer i2c.Close()
+
+	// Create LCD object (16 columns, 2 rows)
+	lcd, err := charLCDRGBI2C.New(i2c, 16, 2)
+	if err != nil {
+		log.Fatalf("Failed to initialize LCD: %v", err)
+	}
+	defer lcd.Close(charLCDRGBI2C.CloseOptions{Clear: true, LEDOff: true})
+
+	// Connect to the broker, Home Assistant sees the panel go offline
+	// through the will when the connection drops
+	config := mqttbridge.Config{}
+	opts := mqtt.NewClientOptions().
+		AddBroker("tcp://localhost:1883").
+		SetClientID("charlcd").
+		SetWill(config.StatusTopic(), "offline", 0, true)
+	client := mqtt.NewClient(opts)
+	if token := client.Connect(); token.Wait() && token.Error() != nil {
+		log.Fatalf("Failed to connect to MQTT broker: %v", token.Error())
+	}
+	defer client.Disconnect(250)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := mqttbridge.New(client, lcd, config).Run(ctx); err != nil && ctx.Err() == nil {
+		log.Printf("MQTT bridge stopped: %v", err)
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"io"
+	"log"
+	"os"
+
+	"github.com/googolgl/go-i2c"
+	"github.com/googolgl/go-mcp23017"
+	"github.com/jyap808/charLCDRGBI2C"
+)
+
+func main() {
+	// Initialize I2C
+	i2c, err := i2c.New(mcp23017.DefI2CAdr, "/dev/i2c-1")
+	if err != nil {
+		log.Fatalf("Failed to initialize I2C: %v", err)
+	}
+	defer i2c.Close()
+
+	// Create LCD object (16 columns, 2 rows)
+	lcd, err := charLCDRGBI2C.New(i2c, 16, 2)
+	if err != nil {
+		log.Fatalf("Failed to initialize LCD: %v", err)
+	}
+
+	Terminal(lcd)
+}
+
+// Terminal copies standard input to the LCD, e.g.
+//
+//	ping 8.8.8.8 | ./terminal
+func Terminal(lcd *charLCDRGBI2C.CharLCDRGBI2C) {
+	log.Println("Starting Terminal Demo")
+
+	// Green LED while reading
+	lcd.Write([]byte("\x1b[32m"))
+
+	if _, err := io.Copy(lcd, os.Stdin); err != nil {
+		log.Printf("Error copying input: %v", err)
+	}
+
+	// Turn off the LED
+	lcd.Write([]byte("\x1b[0m"))
+}
@@ -0,0 +1,71 @@
+package charLCDRGBI2C
+
+// framebuffer mirrors the characters shown on the display so that lines
+// can be read back, scrolled and redrawn
+type framebuffer struct {
+	cells  [][]byte // Character codes by row and column
+	row    int      // Row the address counter points at, -1 when in CGRAM
+	column int      // Column the address counter points at
+}
+
+func newFramebuffer(columns, lines int) framebuffer {
+	fb := framebuffer{cells: make([][]byte, lines)}
+	for row := range fb.cells {
+		fb.cells[row] = make([]byte, columns)
+	}
+	fb.clear()
+	return fb
+}
+
+// clear blanks every cell and homes the address counter
+func (fb *framebuffer) clear() {
+	for _, line := range fb.cells {
+		for column := range line {
+			line[column] = ' '
+		}
+	}
+	fb.row, fb.column = 0, 0
+}
+
+// moveTo points the address counter at a cell
+func (fb *framebuffer) moveTo(column, row int) {
+	fb.row, fb.column = row, column
+}
+
+// detach marks the address counter as pointing into CGRAM, characters
+// written after it do not end up on the display
+func (fb *framebuffer) detach() {
+	fb.row = -1
+}
+
+// put stores a character at the address counter and advances it in the
+// direction of the entry mode
+func (fb *framebuffer) put(character byte, leftToRight bool) {
+	if fb.row < 0 || fb.row >= len(fb.cells) {
+		return
+	}
+	line := fb.cells[fb.row]
+	if fb.column >= 0 && fb.column < len(line) {
+		line[fb.column] = character
+	}
+	if leftToRight {
+		fb.column++
+	} else {
+		fb.column--
+	}
+}
+
+// at reports whether the address counter points at a cell
+func (fb *framebuffer) at(column, row int) bool {
+	return fb.row == row && fb.column == column
+}
+
+// scroll moves every line up by one and blanks the last line
+func (fb *framebuffer) scroll() {
+	first := fb.cells[0]
+	copy(fb.cells, fb.cells[1:])
+	for column := range first {
+		first[column] = ' '
+	}
+	fb.cells[len(fb.cells)-1] = first
+}
@@ -0,0 +1,92 @@
+package charLCDRGBI2C
+
+import "fmt"
+
+// Geometry describes the character layout of a panel and the DDRAM
+// address each visible row starts at.
+type Geometry struct {
+	Columns     int    // Number of visible columns
+	Lines       int    // Number of visible lines
+	RowOffsets  []byte // DDRAM start address of each line
+	Controllers int    // Number of HD44780 controllers, 0 means 1
+}
+
+// Geometry profiles for common HD44780 panels
+var (
+	Geometry16x1 = Geometry{Columns: 16, Lines: 1, RowOffsets: []byte{0x00}}
+	Geometry20x1 = Geometry{Columns: 20, Lines: 1, RowOffsets: []byte{0x00}}
+	Geometry40x1 = Geometry{Columns: 40, Lines: 1, RowOffsets: []byte{0x00}}
+	Geometry8x2  = Geometry{Columns: 8, Lines: 2, RowOffsets: []byte{0x00, 0x40}}
+	Geometry16x2 = Geometry{Columns: 16, Lines: 2, RowOffsets: []byte{0x00, 0x40}}
+	Geometry20x2 = Geometry{Columns: 20, Lines: 2, RowOffsets: []byte{0x00, 0x40}}
+	Geometry24x2 = Geometry{Columns: 24, Lines: 2, RowOffsets: []byte{0x00, 0x40}}
+	Geometry40x2 = Geometry{Columns: 40, Lines: 2, RowOffsets: []byte{0x00, 0x40}}
+	Geometry16x4 = Geometry{Columns: 16, Lines: 4, RowOffsets: []byte{0x00, 0x40, 0x10, 0x50}}
+	Geometry20x4 = Geometry{Columns: 20, Lines: 4, RowOffsets: []byte{0x00, 0x40, 0x14, 0x54}}
+
+	// 40x4 panels are two 40x2 controllers stacked on top of each other,
+	// see WithSecondEnablePin
+	Geometry40x4 = Geometry{Columns: 40, Lines: 4, RowOffsets: []byte{0x00, 0x40, 0x00, 0x40}, Controllers: 2}
+)
+
+// geometries lists the profiles LookupGeometry knows about
+var geometries = []Geometry{
+	Geometry16x1, Geometry20x1, Geometry40x1,
+	Geometry8x2, Geometry16x2, Geometry20x2, Geometry24x2, Geometry40x2,
+	Geometry16x4, Geometry20x4, Geometry40x4,
+}
+
+// LookupGeometry returns the profile for a columns x lines panel
+func LookupGeometry(columns, lines int) (Geometry, error) {
+	for _, g := range geometries {
+		if g.Columns == columns && g.Lines == lines {
+			return g, nil
+		}
+	}
+	return Geometry{}, fmt.Errorf("unsupported LCD geometry %dx%d", columns, lines)
+}
+
+// controllers returns the number of HD44780 controllers driving the panel
+func (g Geometry) controllers() int {
+	if g.Controllers < 1 {
+		return 1
+	}
+	return g.Controllers
+}
+
+// controllerLines returns the number of lines each controller drives
+func (g Geometry) controllerLines() int {
+	return g.Lines / g.controllers()
+}
+
+// Validate checks that every row fits inside the controller's DDRAM
+func (g Geometry) Validate() error {
+	if g.Columns <= 0 || g.Lines <= 0 {
+		return fmt.Errorf("invalid LCD geometry %dx%d", g.Columns, g.Lines)
+	}
+	if len(g.RowOffsets) != g.Lines {
+		return fmt.Errorf("LCD geometry %dx%d needs %d row offsets, got %d",
+			g.Columns, g.Lines, g.Lines, len(g.RowOffsets))
+	}
+	if g.Lines%g.controllers() != 0 {
+		return fmt.Errorf("LCD geometry %dx%d can not be split across %d controllers",
+			g.Columns, g.Lines, g.controllers())
+	}
+
+	// In 1-line mode DDRAM is a single 80 byte line, in 2-line mode it is
+	// split into two 40 byte lines at 0x00 and 0x40
+	for row, offset := range g.RowOffsets {
+		start, size := 0x00, 80
+		if g.controllerLines() > 1 {
+			size = 40
+			if offset >= 0x40 {
+				start = 0x40
+			}
+		}
+		if int(offset) < start || int(offset)+g.Columns > start+size {
+			return fmt.Errorf("LCD geometry %dx%d: row %d at 0x%02X does not fit in DDRAM",
+				g.Columns, g.Lines, row, offset)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package charLCDRGBI2C_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jyap808/charLCDRGBI2C"
+	"github.com/jyap808/charLCDRGBI2C/sim"
+)
+
+func TestLookupGeometry(t *testing.T) {
+	tests := []struct {
+		columns, lines int
+		want           charLCDRGBI2C.Geometry
+	}{
+		{16, 1, charLCDRGBI2C.Geometry16x1},
+		{16, 2, charLCDRGBI2C.Geometry16x2},
+		{16, 4, charLCDRGBI2C.Geometry16x4},
+		{20, 4, charLCDRGBI2C.Geometry20x4},
+		{40, 2, charLCDRGBI2C.Geometry40x2},
+		{40, 4, charLCDRGBI2C.Geometry40x4},
+	}
+	for _, tt := range tests {
+		got, err := charLCDRGBI2C.LookupGeometry(tt.columns, tt.lines)
+		if err != nil {
+			t.Errorf("%dx%d: %v", tt.columns, tt.lines, err)
+			continue
+		}
+		if got.Columns != tt.want.Columns || got.Lines != tt.want.Lines ||
+			string(got.RowOffsets) != string(tt.want.RowOffsets) || got.Controllers != tt.want.Controllers {
+			t.Errorf("%dx%d: got %+v, want %+v", tt.columns, tt.lines, got, tt.want)
+		}
+	}
+
+	for _, size := range [][2]int{{0, 2}, {16, 3}, {80, 1}} {
+		if _, err := charLCDRGBI2C.LookupGeometry(size[0], size[1]); err == nil {
+			t.Errorf("%dx%d: no error", size[0], size[1])
+		}
+	}
+}
+
+func TestGeometryValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		geometry charLCDRGBI2C.Geometry
+		valid    bool
+	}{
+		{"16x1", charLCDRGBI2C.Geometry16x1, true},
+		{"16x2", charLCDRGBI2C.Geometry16x2, true},
+		{"16x4", charLCDRGBI2C.Geometry16x4, true},
+		{"20x4", charLCDRGBI2C.Geometry20x4, true},
+		{"40x2", charLCDRGBI2C.Geometry40x2, true},
+		{"40x4", charLCDRGBI2C.Geometry40x4, true},
+		{"80x1", charLCDRGBI2C.Geometry{Columns: 80, Lines: 1, RowOffsets: []byte{0x00}}, true},
+		{"no columns", charLCDRGBI2C.Geometry{Columns: 0, Lines: 1, RowOffsets: []byte{0x00}}, false},
+		{"no lines", charLCDRGBI2C.Geometry{Columns: 16}, false},
+		{"missing offset", charLCDRGBI2C.Geometry{Columns: 16, Lines: 2, RowOffsets: []byte{0x00}}, false},
+		{"past first line", charLCDRGBI2C.Geometry{Columns: 20, Lines: 2, RowOffsets: []byte{0x20, 0x40}}, false},
+		{"past second line", charLCDRGBI2C.Geometry{Columns: 16, Lines: 2, RowOffsets: []byte{0x00, 0x60}}, false},
+		{"past 1-line DDRAM", charLCDRGBI2C.Geometry{Columns: 81, Lines: 1, RowOffsets: []byte{0x00}}, false},
+		{"uneven controllers", charLCDRGBI2C.Geometry{Columns: 40, Lines: 3, RowOffsets: []byte{0x00, 0x40, 0x00}, Controllers: 2}, false},
+	}
+	for _, tt := range tests {
+		err := tt.geometry.Validate()
+		if tt.valid && err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("%s: no error", tt.name)
+		}
+	}
+}
+
+// TestMessageWraps checks that a message longer than a line continues at
+// the start of the next visible line on the glass, whatever the DDRAM
+// layout of the panel
+func TestMessageWraps(t *testing.T) {
+	for _, geometry := range []charLCDRGBI2C.Geometry{
+		charLCDRGBI2C.Geometry16x4,
+		charLCDRGBI2C.Geometry20x4,
+		charLCDRGBI2C.Geometry40x2,
+	} {
+		lcd, dev := sim.NewLCD(t, geometry, sim.Wiring{})
+
+		var message, want []string
+		for row := range geometry.Lines {
+			line := strings.Repeat(string(rune('A'+row)), geometry.Columns)
+			message = append(message, line)
+			want = append(want, line)
+		}
+		// One character more than fits is dropped
+		lcd.Message(strings.Join(message, "") + "Z")
+
+		for row, line := range dev.Codes() {
+			if string(line) != want[row] {
+				t.Errorf("%dx%d line %d shows %q, want %q", geometry.Columns, geometry.Lines, row, line, want[row])
+			}
+		}
+		if text := lcd.Text(); strings.Join(text, "\n") != strings.Join(want, "\n") {
+			t.Errorf("%dx%d Text() is %q, want %q", geometry.Columns, geometry.Lines, text, want)
+		}
+	}
+}
+
+// newDualController returns a 40x4 LCD on a board with the second enable
+// line on the select button's pin
+func newDualController(t *testing.T) (*charLCDRGBI2C.CharLCDRGBI2C, *sim.Device) {
+	t.Helper()
+	enable2 := charLCDRGBI2C.SelectButton
+	return sim.NewLCD(t, charLCDRGBI2C.Geometry40x4,
+		sim.Wiring{EnablePins: []string{charLCDRGBI2C.LcdEnablePin, enable2}},
+		charLCDRGBI2C.WithSecondEnablePin(enable2),
+		charLCDRGBI2C.WithButtons(charLCDRGBI2C.LeftButton, charLCDRGBI2C.UpButton,
+			charLCDRGBI2C.DownButton, charLCDRGBI2C.RightButton),
+	)
+}
+
+func TestDualControllerRouting(t *testing.T) {
+	lcd, dev := newDualController(t)
+	if dev.Controller(0) == dev.Controller(2) {
+		t.Fatal("rows 0 and 2 share a controller")
+	}
+
+	for row, text := range []string{"top", "second", "third", "bottom"} {
+		lcd.CursorPosition(row, row)
+		lcd.Message(text)
+	}
+	want := []string{"top", " second", "  third", "   bottom"}
+	for row, line := range dev.Codes() {
+		if got := strings.TrimRight(string(line), " "); got != want[row] {
+			t.Errorf("line %d shows %q, want %q", row, got, want[row])
+		}
+	}
+
+	// Rows 2 and 3 are the first and second line of the second controller
+	if got := dev.Controller(2).Char(0x00, 2); got != 't' {
+		t.Errorf("second controller line 1 column 2 is %q, want 't'", got)
+	}
+	if got := dev.Controller(3).Char(0x40, 3); got != 'b' {
+		t.Errorf("second controller line 2 column 3 is %q, want 'b'", got)
+	}
+	if got := dev.Controller(0).Char(0x00, 2); got != 'p' {
+		t.Errorf("first controller line 1 column 2 is %q, want 'p'", got)
+	}
+
+	// Only the controller the cursor is on shows it
+	lcd.SetCursor(true)
+	lcd.CursorPosition(7, 3)
+	if column, row, _, ok := dev.Cursor(); !ok || column != 7 || row != 3 {
+		t.Errorf("cursor at %d,%d shown %v, want 7,3", column, row, ok)
+	}
+	if dev.Controller(0).CursorOn {
+		t.Error("first controller shows a cursor too")
+	}
+}
+
+func TestDualControllerClear(t *testing.T) {
+	lcd, dev := newDualController(t)
+	lcd.Message("one\ntwo\nthree\nfour")
+	lcd.Clear()
+
+	for row, line := range dev.Codes() {
+		if got := strings.TrimRight(string(line), " "); got != "" {
+			t.Errorf("line %d shows %q after Clear", row, got)
+		}
+	}
+	lcd.Message("again")
+	if got := strings.TrimRight(string(dev.Codes()[0]), " "); got != "again" {
+		t.Errorf("line 0 shows %q after Clear, want \"again\"", got)
+	}
+}
@@ -0,0 +1,227 @@
+package grpcapi
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/jyap808/charLCDRGBI2C"
+	"google.golang.org/grpc"
+)
+
+// Client drives a remote panel through the Display service. It implements
+// charLCDRGBI2C.Panel.
+//
+// The Display methods do not return errors, the first failed call is kept
+// and returned by Err.
+type Client struct {
+	Timeout time.Duration // Time each call may take, zero means 5s
+
+	rpc     DisplayClient
+	columns int
+	lines   int
+
+	mu          sync.Mutex
+	err         error
+	row, column int // Cursor position the next Message starts at
+}
+
+var _ charLCDRGBI2C.Panel = (*Client)(nil)
+
+// NewClient returns a client on conn, asking the server for the size of the
+// display
+func NewClient(ctx context.Context, conn grpc.ClientConnInterface) (*Client, error) {
+	c := &Client{rpc: NewDisplayClient(conn)}
+	state, err := c.rpc.GetState(ctx, &GetStateRequest{})
+	if err != nil {
+		return nil, err
+	}
+	c.columns, c.lines = int(state.Columns), int(state.Lines)
+	return c, nil
+}
+
+// Err returns the first error of a call made through a method that does
+// not return errors
+func (c *Client) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// Size returns the number of columns and lines of the LCD
+func (c *Client) Size() (columns, lines int) {
+	return c.columns, c.lines
+}
+
+// Clear clears the display
+func (c *Client) Clear() {
+	c.mu.Lock()
+	c.row, c.column = 0, 0
+	c.mu.Unlock()
+
+	ctx, cancel := c.context()
+	defer cancel()
+	_, err := c.rpc.Clear(ctx, &ClearRequest{})
+	c.fail(err)
+}
+
+// CursorPosition moves the cursor, the next Message starts there
+func (c *Client) CursorPosition(column, row int) {
+	column = min(max(column, 0), c.columns-1)
+	row = min(max(row, 0), c.lines-1)
+
+	c.mu.Lock()
+	c.row, c.column = row, column
+	c.mu.Unlock()
+
+	ctx, cancel := c.context()
+	defer cancel()
+	_, err := c.rpc.MoveCursor(ctx, &MoveCursorRequest{Row: int32(row), Column: int32(column)})
+	c.fail(err)
+}
+
+// Message displays text at the cursor like CharLCDRGBI2C.Message
+func (c *Client) Message(message string) {
+	c.mu.Lock()
+	row, column := c.row, c.column
+	c.row, c.column = 0, 0
+	c.mu.Unlock()
+
+	ctx, cancel := c.context()
+	defer cancel()
+	_, err := c.rpc.WriteText(ctx, &WriteTextRequest{Row: int32(row), Column: int32(column), Text: message})
+	c.fail(err)
+}
+
+// CreateChar defines a custom character
+func (c *Client) CreateChar(location byte, pattern []byte) {
+	ctx, cancel := c.context()
+	defer cancel()
+	_, err := c.rpc.DefineGlyph(ctx, &DefineGlyphRequest{Location: uint32(location), Pattern: pattern})
+	c.fail(err)
+}
+
+// SetCursor shows or hides the cursor
+func (c *Client) SetCursor(show bool) {
+	c.setDisplayControl(&SetDisplayControlRequest{Cursor: &show})
+}
+
+// SetBlink turns cursor blinking on or off
+func (c *Client) SetBlink(blink bool) {
+	c.setDisplayControl(&SetDisplayControlRequest{Blink: &blink})
+}
+
+// SetDisplay turns the display on or off
+func (c *Client) SetDisplay(enable bool) {
+	c.setDisplayControl(&SetDisplayControlRequest{Display: &enable})
+}
+
+// setDisplayControl changes the display control flags set in req
+func (c *Client) setDisplayControl(req *SetDisplayControlRequest) {
+	ctx, cancel := c.context()
+	defer cancel()
+	_, err := c.rpc.SetDisplayControl(ctx, req)
+	c.fail(err)
+}
+
+// SetColor sets the RGB LED color (values from 0-100)
+func (c *Client) SetColor(red, green, blue int) {
+	ctx, cancel := c.context()
+	defer cancel()
+	color := &Color{Red: int32(red), Green: int32(green), Blue: int32(blue)}
+	_, err := c.rpc.SetColor(ctx, &SetColorRequest{Color: color})
+	c.fail(err)
+}
+
+// SetBacklight turns the backlight on or off
+func (c *Client) SetBacklight(on bool) error {
+	ctx, cancel := c.context()
+	defer cancel()
+	_, err := c.rpc.SetBacklight(ctx, &SetBacklightRequest{On: on})
+	return err
+}
+
+// Text returns the characters shown on each line
+func (c *Client) Text() []string {
+	return c.state().GetText()
+}
+
+// Color returns the RGB LED color (values from 0-100)
+func (c *Client) Color() (red, green, blue int) {
+	color := c.state().GetColor()
+	return int(color.GetRed()), int(color.GetGreen()), int(color.GetBlue())
+}
+
+// Backlight reports whether the backlight is on
+func (c *Client) Backlight() bool {
+	return c.state().GetBacklight()
+}
+
+// state returns the state of the display, nil when the call failed
+func (c *Client) state() *State {
+	ctx, cancel := c.context()
+	defer cancel()
+	state, err := c.rpc.GetState(ctx, &GetStateRequest{})
+	c.fail(err)
+	return state
+}
+
+// WatchButtons sends an event for every press and release. The server polls
+// the buttons, interval is ignored. The channel is closed when ctx is done
+// or the stream ends.
+func (c *Client) WatchButtons(ctx context.Context, interval time.Duration) <-chan charLCDRGBI2C.ButtonEvent {
+	events := make(chan charLCDRGBI2C.ButtonEvent, len(charLCDRGBI2C.Buttons))
+
+	stream, err := c.rpc.StreamButtons(ctx, &StreamButtonsRequest{})
+	if err != nil {
+		c.fail(err)
+		close(events)
+		return events
+	}
+
+	go func() {
+		defer close(events)
+		for {
+			event, err := stream.Recv()
+			if err != nil {
+				if err != io.EOF && ctx.Err() == nil {
+					c.fail(err)
+				}
+				return
+			}
+			button, ok := charLCDRGBI2C.ButtonByName(event.Button)
+			if !ok {
+				continue
+			}
+
+			select {
+			case events <- charLCDRGBI2C.ButtonEvent{Button: button, Pressed: event.Pressed, Time: event.Time.AsTime()}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events
+}
+
+// context returns the context of a call
+func (c *Client) context() (context.Context, context.CancelFunc) {
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	return context.WithTimeout(context.Background(), timeout)
+}
+
+// fail keeps the first error
+func (c *Client) fail(err error) {
+	if err == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err == nil {
+		c.err = err
+	}
+}
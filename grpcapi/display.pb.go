@@ -0,0 +1,1051 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: display.proto
+
+package grpcapi
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type WriteTextRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Row    int32                  `protobuf:"varint,1,opt,name=row,proto3" json:"row,omitempty"`
+	Column int32                  `protobuf:"varint,2,opt,name=column,proto3" json:"column,omitempty"`
+	// HD44780 character codes, "\n" starts the next line
+	Text          string `protobuf:"bytes,3,opt,name=text,proto3" json:"text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WriteTextRequest) Reset() {
+	*x = WriteTextRequest{}
+	mi := &file_display_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WriteTextRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WriteTextRequest) ProtoMessage() {}
+
+func (x *WriteTextRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_display_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WriteTextRequest.ProtoReflect.Descriptor instead.
+func (*WriteTextRequest) Descriptor() ([]byte, []int) {
+	return file_display_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *WriteTextRequest) GetRow() int32 {
+	if x != nil {
+		return x.Row
+	}
+	return 0
+}
+
+func (x *WriteTextRequest) GetColumn() int32 {
+	if x != nil {
+		return x.Column
+	}
+	return 0
+}
+
+func (x *WriteTextRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+type WriteTextResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WriteTextResponse) Reset() {
+	*x = WriteTextResponse{}
+	mi := &file_display_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WriteTextResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WriteTextResponse) ProtoMessage() {}
+
+func (x *WriteTextResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_display_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WriteTextResponse.ProtoReflect.Descriptor instead.
+func (*WriteTextResponse) Descriptor() ([]byte, []int) {
+	return file_display_proto_rawDescGZIP(), []int{1}
+}
+
+type ClearRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClearRequest) Reset() {
+	*x = ClearRequest{}
+	mi := &file_display_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearRequest) ProtoMessage() {}
+
+func (x *ClearRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_display_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearRequest.ProtoReflect.Descriptor instead.
+func (*ClearRequest) Descriptor() ([]byte, []int) {
+	return file_display_proto_rawDescGZIP(), []int{2}
+}
+
+type ClearResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClearResponse) Reset() {
+	*x = ClearResponse{}
+	mi := &file_display_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearResponse) ProtoMessage() {}
+
+func (x *ClearResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_display_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearResponse.ProtoReflect.Descriptor instead.
+func (*ClearResponse) Descriptor() ([]byte, []int) {
+	return file_display_proto_rawDescGZIP(), []int{3}
+}
+
+type MoveCursorRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Row           int32                  `protobuf:"varint,1,opt,name=row,proto3" json:"row,omitempty"`
+	Column        int32                  `protobuf:"varint,2,opt,name=column,proto3" json:"column,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoveCursorRequest) Reset() {
+	*x = MoveCursorRequest{}
+	mi := &file_display_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveCursorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveCursorRequest) ProtoMessage() {}
+
+func (x *MoveCursorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_display_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveCursorRequest.ProtoReflect.Descriptor instead.
+func (*MoveCursorRequest) Descriptor() ([]byte, []int) {
+	return file_display_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *MoveCursorRequest) GetRow() int32 {
+	if x != nil {
+		return x.Row
+	}
+	return 0
+}
+
+func (x *MoveCursorRequest) GetColumn() int32 {
+	if x != nil {
+		return x.Column
+	}
+	return 0
+}
+
+type MoveCursorResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoveCursorResponse) Reset() {
+	*x = MoveCursorResponse{}
+	mi := &file_display_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveCursorResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveCursorResponse) ProtoMessage() {}
+
+func (x *MoveCursorResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_display_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveCursorResponse.ProtoReflect.Descriptor instead.
+func (*MoveCursorResponse) Descriptor() ([]byte, []int) {
+	return file_display_proto_rawDescGZIP(), []int{5}
+}
+
+// Unset fields are left as they are
+type SetDisplayControlRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Display       *bool                  `protobuf:"varint,1,opt,name=display,proto3,oneof" json:"display,omitempty"`
+	Cursor        *bool                  `protobuf:"varint,2,opt,name=cursor,proto3,oneof" json:"cursor,omitempty"`
+	Blink         *bool                  `protobuf:"varint,3,opt,name=blink,proto3,oneof" json:"blink,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetDisplayControlRequest) Reset() {
+	*x = SetDisplayControlRequest{}
+	mi := &file_display_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetDisplayControlRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetDisplayControlRequest) ProtoMessage() {}
+
+func (x *SetDisplayControlRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_display_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetDisplayControlRequest.ProtoReflect.Descriptor instead.
+func (*SetDisplayControlRequest) Descriptor() ([]byte, []int) {
+	return file_display_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SetDisplayControlRequest) GetDisplay() bool {
+	if x != nil && x.Display != nil {
+		return *x.Display
+	}
+	return false
+}
+
+func (x *SetDisplayControlRequest) GetCursor() bool {
+	if x != nil && x.Cursor != nil {
+		return *x.Cursor
+	}
+	return false
+}
+
+func (x *SetDisplayControlRequest) GetBlink() bool {
+	if x != nil && x.Blink != nil {
+		return *x.Blink
+	}
+	return false
+}
+
+type SetDisplayControlResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetDisplayControlResponse) Reset() {
+	*x = SetDisplayControlResponse{}
+	mi := &file_display_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetDisplayControlResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetDisplayControlResponse) ProtoMessage() {}
+
+func (x *SetDisplayControlResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_display_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetDisplayControlResponse.ProtoReflect.Descriptor instead.
+func (*SetDisplayControlResponse) Descriptor() ([]byte, []int) {
+	return file_display_proto_rawDescGZIP(), []int{7}
+}
+
+// Color values are from 0-100
+type Color struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Red           int32                  `protobuf:"varint,1,opt,name=red,proto3" json:"red,omitempty"`
+	Green         int32                  `protobuf:"varint,2,opt,name=green,proto3" json:"green,omitempty"`
+	Blue          int32                  `protobuf:"varint,3,opt,name=blue,proto3" json:"blue,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Color) Reset() {
+	*x = Color{}
+	mi := &file_display_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Color) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Color) ProtoMessage() {}
+
+func (x *Color) ProtoReflect() protoreflect.Message {
+	mi := &file_display_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Color.ProtoReflect.Descriptor instead.
+func (*Color) Descriptor() ([]byte, []int) {
+	return file_display_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Color) GetRed() int32 {
+	if x != nil {
+		return x.Red
+	}
+	return 0
+}
+
+func (x *Color) GetGreen() int32 {
+	if x != nil {
+		return x.Green
+	}
+	return 0
+}
+
+func (x *Color) GetBlue() int32 {
+	if x != nil {
+		return x.Blue
+	}
+	return 0
+}
+
+type SetColorRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Color         *Color                 `protobuf:"bytes,1,opt,name=color,proto3" json:"color,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetColorRequest) Reset() {
+	*x = SetColorRequest{}
+	mi := &file_display_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetColorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetColorRequest) ProtoMessage() {}
+
+func (x *SetColorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_display_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetColorRequest.ProtoReflect.Descriptor instead.
+func (*SetColorRequest) Descriptor() ([]byte, []int) {
+	return file_display_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SetColorRequest) GetColor() *Color {
+	if x != nil {
+		return x.Color
+	}
+	return nil
+}
+
+type SetColorResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetColorResponse) Reset() {
+	*x = SetColorResponse{}
+	mi := &file_display_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetColorResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetColorResponse) ProtoMessage() {}
+
+func (x *SetColorResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_display_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetColorResponse.ProtoReflect.Descriptor instead.
+func (*SetColorResponse) Descriptor() ([]byte, []int) {
+	return file_display_proto_rawDescGZIP(), []int{10}
+}
+
+type SetBacklightRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	On            bool                   `protobuf:"varint,1,opt,name=on,proto3" json:"on,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetBacklightRequest) Reset() {
+	*x = SetBacklightRequest{}
+	mi := &file_display_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetBacklightRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetBacklightRequest) ProtoMessage() {}
+
+func (x *SetBacklightRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_display_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetBacklightRequest.ProtoReflect.Descriptor instead.
+func (*SetBacklightRequest) Descriptor() ([]byte, []int) {
+	return file_display_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SetBacklightRequest) GetOn() bool {
+	if x != nil {
+		return x.On
+	}
+	return false
+}
+
+type SetBacklightResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetBacklightResponse) Reset() {
+	*x = SetBacklightResponse{}
+	mi := &file_display_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetBacklightResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetBacklightResponse) ProtoMessage() {}
+
+func (x *SetBacklightResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_display_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetBacklightResponse.ProtoReflect.Descriptor instead.
+func (*SetBacklightResponse) Descriptor() ([]byte, []int) {
+	return file_display_proto_rawDescGZIP(), []int{12}
+}
+
+type DefineGlyphRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Location from 0-7
+	Location uint32 `protobuf:"varint,1,opt,name=location,proto3" json:"location,omitempty"`
+	// One byte per pixel row, 8 rows or 11 with the 5x10 font
+	Pattern       []byte `protobuf:"bytes,2,opt,name=pattern,proto3" json:"pattern,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DefineGlyphRequest) Reset() {
+	*x = DefineGlyphRequest{}
+	mi := &file_display_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DefineGlyphRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DefineGlyphRequest) ProtoMessage() {}
+
+func (x *DefineGlyphRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_display_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DefineGlyphRequest.ProtoReflect.Descriptor instead.
+func (*DefineGlyphRequest) Descriptor() ([]byte, []int) {
+	return file_display_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *DefineGlyphRequest) GetLocation() uint32 {
+	if x != nil {
+		return x.Location
+	}
+	return 0
+}
+
+func (x *DefineGlyphRequest) GetPattern() []byte {
+	if x != nil {
+		return x.Pattern
+	}
+	return nil
+}
+
+type DefineGlyphResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DefineGlyphResponse) Reset() {
+	*x = DefineGlyphResponse{}
+	mi := &file_display_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DefineGlyphResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DefineGlyphResponse) ProtoMessage() {}
+
+func (x *DefineGlyphResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_display_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DefineGlyphResponse.ProtoReflect.Descriptor instead.
+func (*DefineGlyphResponse) Descriptor() ([]byte, []int) {
+	return file_display_proto_rawDescGZIP(), []int{14}
+}
+
+type StreamButtonsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamButtonsRequest) Reset() {
+	*x = StreamButtonsRequest{}
+	mi := &file_display_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamButtonsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamButtonsRequest) ProtoMessage() {}
+
+func (x *StreamButtonsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_display_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamButtonsRequest.ProtoReflect.Descriptor instead.
+func (*StreamButtonsRequest) Descriptor() ([]byte, []int) {
+	return file_display_proto_rawDescGZIP(), []int{15}
+}
+
+type ButtonEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Button name, e.g. "select"
+	Button        string                 `protobuf:"bytes,1,opt,name=button,proto3" json:"button,omitempty"`
+	Pressed       bool                   `protobuf:"varint,2,opt,name=pressed,proto3" json:"pressed,omitempty"`
+	Time          *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=time,proto3" json:"time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ButtonEvent) Reset() {
+	*x = ButtonEvent{}
+	mi := &file_display_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ButtonEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ButtonEvent) ProtoMessage() {}
+
+func (x *ButtonEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_display_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ButtonEvent.ProtoReflect.Descriptor instead.
+func (*ButtonEvent) Descriptor() ([]byte, []int) {
+	return file_display_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ButtonEvent) GetButton() string {
+	if x != nil {
+		return x.Button
+	}
+	return ""
+}
+
+func (x *ButtonEvent) GetPressed() bool {
+	if x != nil {
+		return x.Pressed
+	}
+	return false
+}
+
+func (x *ButtonEvent) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+type GetStateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStateRequest) Reset() {
+	*x = GetStateRequest{}
+	mi := &file_display_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStateRequest) ProtoMessage() {}
+
+func (x *GetStateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_display_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStateRequest.ProtoReflect.Descriptor instead.
+func (*GetStateRequest) Descriptor() ([]byte, []int) {
+	return file_display_proto_rawDescGZIP(), []int{17}
+}
+
+type State struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Columns int32                  `protobuf:"varint,1,opt,name=columns,proto3" json:"columns,omitempty"`
+	Lines   int32                  `protobuf:"varint,2,opt,name=lines,proto3" json:"lines,omitempty"`
+	// The characters on each line as HD44780 character codes
+	Text          []string `protobuf:"bytes,3,rep,name=text,proto3" json:"text,omitempty"`
+	Color         *Color   `protobuf:"bytes,4,opt,name=color,proto3" json:"color,omitempty"`
+	Backlight     bool     `protobuf:"varint,5,opt,name=backlight,proto3" json:"backlight,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *State) Reset() {
+	*x = State{}
+	mi := &file_display_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *State) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*State) ProtoMessage() {}
+
+func (x *State) ProtoReflect() protoreflect.Message {
+	mi := &file_display_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use State.ProtoReflect.Descriptor instead.
+func (*State) Descriptor() ([]byte, []int) {
+	return file_display_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *State) GetColumns() int32 {
+	if x != nil {
+		return x.Columns
+	}
+	return 0
+}
+
+func (x *State) GetLines() int32 {
+	if x != nil {
+		return x.Lines
+	}
+	return 0
+}
+
+func (x *State) GetText() []string {
+	if x != nil {
+		return x.Text
+	}
+	return nil
+}
+
+func (x *State) GetColor() *Color {
+	if x != nil {
+		return x.Color
+	}
+	return nil
+}
+
+func (x *State) GetBacklight() bool {
+	if x != nil {
+		return x.Backlight
+	}
+	return false
+}
+
+var File_display_proto protoreflect.FileDescriptor
+
+const file_display_proto_rawDesc = "" +
+	"\n" +
+	"\rdisplay.proto\x12\n" +
+	"charlcd.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"P\n" +
+	"\x10WriteTextRequest\x12\x10\n" +
+	"\x03row\x18\x01 \x01(\x05R\x03row\x12\x16\n" +
+	"\x06column\x18\x02 \x01(\x05R\x06column\x12\x12\n" +
+	"\x04text\x18\x03 \x01(\tR\x04text\"\x13\n" +
+	"\x11WriteTextResponse\"\x0e\n" +
+	"\fClearRequest\"\x0f\n" +
+	"\rClearResponse\"=\n" +
+	"\x11MoveCursorRequest\x12\x10\n" +
+	"\x03row\x18\x01 \x01(\x05R\x03row\x12\x16\n" +
+	"\x06column\x18\x02 \x01(\x05R\x06column\"\x14\n" +
+	"\x12MoveCursorResponse\"\x92\x01\n" +
+	"\x18SetDisplayControlRequest\x12\x1d\n" +
+	"\adisplay\x18\x01 \x01(\bH\x00R\adisplay\x88\x01\x01\x12\x1b\n" +
+	"\x06cursor\x18\x02 \x01(\bH\x01R\x06cursor\x88\x01\x01\x12\x19\n" +
+	"\x05blink\x18\x03 \x01(\bH\x02R\x05blink\x88\x01\x01B\n" +
+	"\n" +
+	"\b_displayB\t\n" +
+	"\a_cursorB\b\n" +
+	"\x06_blink\"\x1b\n" +
+	"\x19SetDisplayControlResponse\"C\n" +
+	"\x05Color\x12\x10\n" +
+	"\x03red\x18\x01 \x01(\x05R\x03red\x12\x14\n" +
+	"\x05green\x18\x02 \x01(\x05R\x05green\x12\x12\n" +
+	"\x04blue\x18\x03 \x01(\x05R\x04blue\":\n" +
+	"\x0fSetColorRequest\x12'\n" +
+	"\x05color\x18\x01 \x01(\v2\x11.charlcd.v1.ColorR\x05color\"\x12\n" +
+	"\x10SetColorResponse\"%\n" +
+	"\x13SetBacklightRequest\x12\x0e\n" +
+	"\x02on\x18\x01 \x01(\bR\x02on\"\x16\n" +
+	"\x14SetBacklightResponse\"J\n" +
+	"\x12DefineGlyphRequest\x12\x1a\n" +
+	"\blocation\x18\x01 \x01(\rR\blocation\x12\x18\n" +
+	"\apattern\x18\x02 \x01(\fR\apattern\"\x15\n" +
+	"\x13DefineGlyphResponse\"\x16\n" +
+	"\x14StreamButtonsRequest\"o\n" +
+	"\vButtonEvent\x12\x16\n" +
+	"\x06button\x18\x01 \x01(\tR\x06button\x12\x18\n" +
+	"\apressed\x18\x02 \x01(\bR\apressed\x12.\n" +
+	"\x04time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\"\x11\n" +
+	"\x0fGetStateRequest\"\x92\x01\n" +
+	"\x05State\x12\x18\n" +
+	"\acolumns\x18\x01 \x01(\x05R\acolumns\x12\x14\n" +
+	"\x05lines\x18\x02 \x01(\x05R\x05lines\x12\x12\n" +
+	"\x04text\x18\x03 \x03(\tR\x04text\x12'\n" +
+	"\x05color\x18\x04 \x01(\v2\x11.charlcd.v1.ColorR\x05color\x12\x1c\n" +
+	"\tbacklight\x18\x05 \x01(\bR\tbacklight2\xb4\x05\n" +
+	"\aDisplay\x12H\n" +
+	"\tWriteText\x12\x1c.charlcd.v1.WriteTextRequest\x1a\x1d.charlcd.v1.WriteTextResponse\x12<\n" +
+	"\x05Clear\x12\x18.charlcd.v1.ClearRequest\x1a\x19.charlcd.v1.ClearResponse\x12K\n" +
+	"\n" +
+	"MoveCursor\x12\x1d.charlcd.v1.MoveCursorRequest\x1a\x1e.charlcd.v1.MoveCursorResponse\x12`\n" +
+	"\x11SetDisplayControl\x12$.charlcd.v1.SetDisplayControlRequest\x1a%.charlcd.v1.SetDisplayControlResponse\x12E\n" +
+	"\bSetColor\x12\x1b.charlcd.v1.SetColorRequest\x1a\x1c.charlcd.v1.SetColorResponse\x12Q\n" +
+	"\fSetBacklight\x12\x1f.charlcd.v1.SetBacklightRequest\x1a .charlcd.v1.SetBacklightResponse\x12N\n" +
+	"\vDefineGlyph\x12\x1e.charlcd.v1.DefineGlyphRequest\x1a\x1f.charlcd.v1.DefineGlyphResponse\x12L\n" +
+	"\rStreamButtons\x12 .charlcd.v1.StreamButtonsRequest\x1a\x17.charlcd.v1.ButtonEvent0\x01\x12:\n" +
+	"\bGetState\x12\x1b.charlcd.v1.GetStateRequest\x1a\x11.charlcd.v1.StateB*Z(github.com/jyap808/charLCDRGBI2C/grpcapib\x06proto3"
+
+var (
+	file_display_proto_rawDescOnce sync.Once
+	file_display_proto_rawDescData []byte
+)
+
+func file_display_proto_rawDescGZIP() []byte {
+	file_display_proto_rawDescOnce.Do(func() {
+		file_display_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_display_proto_rawDesc), len(file_display_proto_rawDesc)))
+	})
+	return file_display_proto_rawDescData
+}
+
+var file_display_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
+var file_display_proto_goTypes = []any{
+	(*WriteTextRequest)(nil),          // 0: charlcd.v1.WriteTextRequest
+	(*WriteTextResponse)(nil),         // 1: charlcd.v1.WriteTextResponse
+	(*ClearRequest)(nil),              // 2: charlcd.v1.ClearRequest
+	(*ClearResponse)(nil),             // 3: charlcd.v1.ClearResponse
+	(*MoveCursorRequest)(nil),         // 4: charlcd.v1.MoveCursorRequest
+	(*MoveCursorResponse)(nil),        // 5: charlcd.v1.MoveCursorResponse
+	(*SetDisplayControlRequest)(nil),  // 6: charlcd.v1.SetDisplayControlRequest
+	(*SetDisplayControlResponse)(nil), // 7: charlcd.v1.SetDisplayControlResponse
+	(*Color)(nil),                     // 8: charlcd.v1.Color
+	(*SetColorRequest)(nil),           // 9: charlcd.v1.SetColorRequest
+	(*SetColorResponse)(nil),          // 10: charlcd.v1.SetColorResponse
+	(*SetBacklightRequest)(nil),       // 11: charlcd.v1.SetBacklightRequest
+	(*SetBacklightResponse)(nil),      // 12: charlcd.v1.SetBacklightResponse
+	(*DefineGlyphRequest)(nil),        // 13: charlcd.v1.DefineGlyphRequest
+	(*DefineGlyphResponse)(nil),       // 14: charlcd.v1.DefineGlyphResponse
+	(*StreamButtonsRequest)(nil),      // 15: charlcd.v1.StreamButtonsRequest
+	(*ButtonEvent)(nil),               // 16: charlcd.v1.ButtonEvent
+	(*GetStateRequest)(nil),           // 17: charlcd.v1.GetStateRequest
+	(*State)(nil),                     // 18: charlcd.v1.State
+	(*timestamppb.Timestamp)(nil),     // 19: google.protobuf.Timestamp
+}
+var file_display_proto_depIdxs = []int32{
+	8,  // 0: charlcd.v1.SetColorRequest.color:type_name -> charlcd.v1.Color
+	19, // 1: charlcd.v1.ButtonEvent.time:type_name -> google.protobuf.Timestamp
+	8,  // 2: charlcd.v1.State.color:type_name -> charlcd.v1.Color
+	0,  // 3: charlcd.v1.Display.WriteText:input_type -> charlcd.v1.WriteTextRequest
+	2,  // 4: charlcd.v1.Display.Clear:input_type -> charlcd.v1.ClearRequest
+	4,  // 5: charlcd.v1.Display.MoveCursor:input_type -> charlcd.v1.MoveCursorRequest
+	6,  // 6: charlcd.v1.Display.SetDisplayControl:input_type -> charlcd.v1.SetDisplayControlRequest
+	9,  // 7: charlcd.v1.Display.SetColor:input_type -> charlcd.v1.SetColorRequest
+	11, // 8: charlcd.v1.Display.SetBacklight:input_type -> charlcd.v1.SetBacklightRequest
+	13, // 9: charlcd.v1.Display.DefineGlyph:input_type -> charlcd.v1.DefineGlyphRequest
+	15, // 10: charlcd.v1.Display.StreamButtons:input_type -> charlcd.v1.StreamButtonsRequest
+	17, // 11: charlcd.v1.Display.GetState:input_type -> charlcd.v1.GetStateRequest
+	1,  // 12: charlcd.v1.Display.WriteText:output_type -> charlcd.v1.WriteTextResponse
+	3,  // 13: charlcd.v1.Display.Clear:output_type -> charlcd.v1.ClearResponse
+	5,  // 14: charlcd.v1.Display.MoveCursor:output_type -> charlcd.v1.MoveCursorResponse
+	7,  // 15: charlcd.v1.Display.SetDisplayControl:output_type -> charlcd.v1.SetDisplayControlResponse
+	10, // 16: charlcd.v1.Display.SetColor:output_type -> charlcd.v1.SetColorResponse
+	12, // 17: charlcd.v1.Display.SetBacklight:output_type -> charlcd.v1.SetBacklightResponse
+	14, // 18: charlcd.v1.Display.DefineGlyph:output_type -> charlcd.v1.DefineGlyphResponse
+	16, // 19: charlcd.v1.Display.StreamButtons:output_type -> charlcd.v1.ButtonEvent
+	18, // 20: charlcd.v1.Display.GetState:output_type -> charlcd.v1.State
+	12, // [12:21] is the sub-list for method output_type
+	3,  // [3:12] is the sub-list for method input_type
+	3,  // [3:3] is the sub-list for extension type_name
+	3,  // [3:3] is the sub-list for extension extendee
+	0,  // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_display_proto_init() }
+func file_display_proto_init() {
+	if File_display_proto != nil {
+		return
+	}
+	file_display_proto_msgTypes[6].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_display_proto_rawDesc), len(file_display_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   19,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_display_proto_goTypes,
+		DependencyIndexes: file_display_proto_depIdxs,
+		MessageInfos:      file_display_proto_msgTypes,
+	}.Build()
+	File_display_proto = out.File
+	file_display_proto_goTypes = nil
+	file_display_proto_depIdxs = nil
+}
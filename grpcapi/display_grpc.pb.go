@@ -0,0 +1,451 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: display.proto
+
+package grpcapi
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Display_WriteText_FullMethodName         = "/charlcd.v1.Display/WriteText"
+	Display_Clear_FullMethodName             = "/charlcd.v1.Display/Clear"
+	Display_MoveCursor_FullMethodName        = "/charlcd.v1.Display/MoveCursor"
+	Display_SetDisplayControl_FullMethodName = "/charlcd.v1.Display/SetDisplayControl"
+	Display_SetColor_FullMethodName          = "/charlcd.v1.Display/SetColor"
+	Display_SetBacklight_FullMethodName      = "/charlcd.v1.Display/SetBacklight"
+	Display_DefineGlyph_FullMethodName       = "/charlcd.v1.Display/DefineGlyph"
+	Display_StreamButtons_FullMethodName     = "/charlcd.v1.Display/StreamButtons"
+	Display_GetState_FullMethodName          = "/charlcd.v1.Display/GetState"
+)
+
+// DisplayClient is the client API for Display service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Display drives a character LCD with an RGB LED and five buttons
+type DisplayClient interface {
+	// WriteText writes text from a position like Message
+	WriteText(ctx context.Context, in *WriteTextRequest, opts ...grpc.CallOption) (*WriteTextResponse, error)
+	// Clear clears the display
+	Clear(ctx context.Context, in *ClearRequest, opts ...grpc.CallOption) (*ClearResponse, error)
+	// MoveCursor moves the cursor
+	MoveCursor(ctx context.Context, in *MoveCursorRequest, opts ...grpc.CallOption) (*MoveCursorResponse, error)
+	// SetDisplayControl turns the display, cursor and blinking on or off
+	SetDisplayControl(ctx context.Context, in *SetDisplayControlRequest, opts ...grpc.CallOption) (*SetDisplayControlResponse, error)
+	// SetColor sets the RGB LED
+	SetColor(ctx context.Context, in *SetColorRequest, opts ...grpc.CallOption) (*SetColorResponse, error)
+	// SetBacklight turns the backlight on or off
+	SetBacklight(ctx context.Context, in *SetBacklightRequest, opts ...grpc.CallOption) (*SetBacklightResponse, error)
+	// DefineGlyph defines a custom character
+	DefineGlyph(ctx context.Context, in *DefineGlyphRequest, opts ...grpc.CallOption) (*DefineGlyphResponse, error)
+	// StreamButtons sends an event for every button press and release
+	StreamButtons(ctx context.Context, in *StreamButtonsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ButtonEvent], error)
+	// GetState returns the size, text, color and backlight of the display
+	GetState(ctx context.Context, in *GetStateRequest, opts ...grpc.CallOption) (*State, error)
+}
+
+type displayClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDisplayClient(cc grpc.ClientConnInterface) DisplayClient {
+	return &displayClient{cc}
+}
+
+func (c *displayClient) WriteText(ctx context.Context, in *WriteTextRequest, opts ...grpc.CallOption) (*WriteTextResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WriteTextResponse)
+	err := c.cc.Invoke(ctx, Display_WriteText_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *displayClient) Clear(ctx context.Context, in *ClearRequest, opts ...grpc.CallOption) (*ClearResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ClearResponse)
+	err := c.cc.Invoke(ctx, Display_Clear_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *displayClient) MoveCursor(ctx context.Context, in *MoveCursorRequest, opts ...grpc.CallOption) (*MoveCursorResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MoveCursorResponse)
+	err := c.cc.Invoke(ctx, Display_MoveCursor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *displayClient) SetDisplayControl(ctx context.Context, in *SetDisplayControlRequest, opts ...grpc.CallOption) (*SetDisplayControlResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetDisplayControlResponse)
+	err := c.cc.Invoke(ctx, Display_SetDisplayControl_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *displayClient) SetColor(ctx context.Context, in *SetColorRequest, opts ...grpc.CallOption) (*SetColorResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetColorResponse)
+	err := c.cc.Invoke(ctx, Display_SetColor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *displayClient) SetBacklight(ctx context.Context, in *SetBacklightRequest, opts ...grpc.CallOption) (*SetBacklightResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetBacklightResponse)
+	err := c.cc.Invoke(ctx, Display_SetBacklight_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *displayClient) DefineGlyph(ctx context.Context, in *DefineGlyphRequest, opts ...grpc.CallOption) (*DefineGlyphResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DefineGlyphResponse)
+	err := c.cc.Invoke(ctx, Display_DefineGlyph_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *displayClient) StreamButtons(ctx context.Context, in *StreamButtonsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ButtonEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Display_ServiceDesc.Streams[0], Display_StreamButtons_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamButtonsRequest, ButtonEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Display_StreamButtonsClient = grpc.ServerStreamingClient[ButtonEvent]
+
+func (c *displayClient) GetState(ctx context.Context, in *GetStateRequest, opts ...grpc.CallOption) (*State, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(State)
+	err := c.cc.Invoke(ctx, Display_GetState_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DisplayServer is the server API for Display service.
+// All implementations must embed UnimplementedDisplayServer
+// for forward compatibility.
+//
+// Display drives a character LCD with an RGB LED and five buttons
+type DisplayServer interface {
+	// WriteText writes text from a position like Message
+	WriteText(context.Context, *WriteTextRequest) (*WriteTextResponse, error)
+	// Clear clears the display
+	Clear(context.Context, *ClearRequest) (*ClearResponse, error)
+	// MoveCursor moves the cursor
+	MoveCursor(context.Context, *MoveCursorRequest) (*MoveCursorResponse, error)
+	// SetDisplayControl turns the display, cursor and blinking on or off
+	SetDisplayControl(context.Context, *SetDisplayControlRequest) (*SetDisplayControlResponse, error)
+	// SetColor sets the RGB LED
+	SetColor(context.Context, *SetColorRequest) (*SetColorResponse, error)
+	// SetBacklight turns the backlight on or off
+	SetBacklight(context.Context, *SetBacklightRequest) (*SetBacklightResponse, error)
+	// DefineGlyph defines a custom character
+	DefineGlyph(context.Context, *DefineGlyphRequest) (*DefineGlyphResponse, error)
+	// StreamButtons sends an event for every button press and release
+	StreamButtons(*StreamButtonsRequest, grpc.ServerStreamingServer[ButtonEvent]) error
+	// GetState returns the size, text, color and backlight of the display
+	GetState(context.Context, *GetStateRequest) (*State, error)
+	mustEmbedUnimplementedDisplayServer()
+}
+
+// UnimplementedDisplayServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedDisplayServer struct{}
+
+func (UnimplementedDisplayServer) WriteText(context.Context, *WriteTextRequest) (*WriteTextResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WriteText not implemented")
+}
+func (UnimplementedDisplayServer) Clear(context.Context, *ClearRequest) (*ClearResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Clear not implemented")
+}
+func (UnimplementedDisplayServer) MoveCursor(context.Context, *MoveCursorRequest) (*MoveCursorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MoveCursor not implemented")
+}
+func (UnimplementedDisplayServer) SetDisplayControl(context.Context, *SetDisplayControlRequest) (*SetDisplayControlResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetDisplayControl not implemented")
+}
+func (UnimplementedDisplayServer) SetColor(context.Context, *SetColorRequest) (*SetColorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetColor not implemented")
+}
+func (UnimplementedDisplayServer) SetBacklight(context.Context, *SetBacklightRequest) (*SetBacklightResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetBacklight not implemented")
+}
+func (UnimplementedDisplayServer) DefineGlyph(context.Context, *DefineGlyphRequest) (*DefineGlyphResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DefineGlyph not implemented")
+}
+func (UnimplementedDisplayServer) StreamButtons(*StreamButtonsRequest, grpc.ServerStreamingServer[ButtonEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamButtons not implemented")
+}
+func (UnimplementedDisplayServer) GetState(context.Context, *GetStateRequest) (*State, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetState not implemented")
+}
+func (UnimplementedDisplayServer) mustEmbedUnimplementedDisplayServer() {}
+func (UnimplementedDisplayServer) testEmbeddedByValue()                 {}
+
+// UnsafeDisplayServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DisplayServer will
+// result in compilation errors.
+type UnsafeDisplayServer interface {
+	mustEmbedUnimplementedDisplayServer()
+}
+
+func RegisterDisplayServer(s grpc.ServiceRegistrar, srv DisplayServer) {
+	// If the following call pancis, it indicates UnimplementedDisplayServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Display_ServiceDesc, srv)
+}
+
+func _Display_WriteText_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WriteTextRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DisplayServer).WriteText(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Display_WriteText_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DisplayServer).WriteText(ctx, req.(*WriteTextRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Display_Clear_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClearRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DisplayServer).Clear(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Display_Clear_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DisplayServer).Clear(ctx, req.(*ClearRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Display_MoveCursor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveCursorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DisplayServer).MoveCursor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Display_MoveCursor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DisplayServer).MoveCursor(ctx, req.(*MoveCursorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Display_SetDisplayControl_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetDisplayControlRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DisplayServer).SetDisplayControl(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Display_SetDisplayControl_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DisplayServer).SetDisplayControl(ctx, req.(*SetDisplayControlRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Display_SetColor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetColorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DisplayServer).SetColor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Display_SetColor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DisplayServer).SetColor(ctx, req.(*SetColorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Display_SetBacklight_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetBacklightRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DisplayServer).SetBacklight(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Display_SetBacklight_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DisplayServer).SetBacklight(ctx, req.(*SetBacklightRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Display_DefineGlyph_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DefineGlyphRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DisplayServer).DefineGlyph(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Display_DefineGlyph_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DisplayServer).DefineGlyph(ctx, req.(*DefineGlyphRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Display_StreamButtons_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamButtonsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DisplayServer).StreamButtons(m, &grpc.GenericServerStream[StreamButtonsRequest, ButtonEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Display_StreamButtonsServer = grpc.ServerStreamingServer[ButtonEvent]
+
+func _Display_GetState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DisplayServer).GetState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Display_GetState_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DisplayServer).GetState(ctx, req.(*GetStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Display_ServiceDesc is the grpc.ServiceDesc for Display service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Display_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "charlcd.v1.Display",
+	HandlerType: (*DisplayServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "WriteText",
+			Handler:    _Display_WriteText_Handler,
+		},
+		{
+			MethodName: "Clear",
+			Handler:    _Display_Clear_Handler,
+		},
+		{
+			MethodName: "MoveCursor",
+			Handler:    _Display_MoveCursor_Handler,
+		},
+		{
+			MethodName: "SetDisplayControl",
+			Handler:    _Display_SetDisplayControl_Handler,
+		},
+		{
+			MethodName: "SetColor",
+			Handler:    _Display_SetColor_Handler,
+		},
+		{
+			MethodName: "SetBacklight",
+			Handler:    _Display_SetBacklight_Handler,
+		},
+		{
+			MethodName: "DefineGlyph",
+			Handler:    _Display_DefineGlyph_Handler,
+		},
+		{
+			MethodName: "GetState",
+			Handler:    _Display_GetState_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamButtons",
+			Handler:       _Display_StreamButtons_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "display.proto",
+}
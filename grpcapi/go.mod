@@ -0,0 +1,19 @@
+module github.com/jyap808/charLCDRGBI2C/grpcapi
+
+go 1.23.6
+
+require (
+	github.com/jyap808/charLCDRGBI2C v0.1.0
+	google.golang.org/grpc v1.75.0
+	google.golang.org/protobuf v1.36.6
+)
+
+require (
+	github.com/googolgl/go-i2c v0.1.1 // indirect
+	github.com/googolgl/go-mcp23017 v0.0.0-20210225115400-eb8b77d91034 // indirect
+	github.com/sirupsen/logrus v1.8.1 // indirect
+	golang.org/x/net v0.41.0 // indirect
+	golang.org/x/sys v0.33.0 // indirect
+	golang.org/x/text v0.26.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250707201910-8d1bb00bc6a7 // indirect
+)
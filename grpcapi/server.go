@@ -0,0 +1,173 @@
+// Package grpcapi serves a panel over gRPC, see display.proto for the
+// service. NewClient returns a charLCDRGBI2C.Panel that drives a remote
+// panel, so code can switch between local and remote displays.
+package grpcapi
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative display.proto
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jyap808/charLCDRGBI2C"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Server implements the Display service on a panel
+type Server struct {
+	UnimplementedDisplayServer
+
+	ButtonInterval time.Duration // Button polling interval, zero means 20ms
+
+	panel charLCDRGBI2C.Panel
+	mu    sync.Mutex // Keeps multi-step updates together
+}
+
+// NewServer returns a service for panel, register it with
+// RegisterDisplayServer
+func NewServer(panel charLCDRGBI2C.Panel) *Server {
+	return &Server{panel: panel}
+}
+
+// WriteText writes text from a position like Message
+func (s *Server) WriteText(ctx context.Context, req *WriteTextRequest) (*WriteTextResponse, error) {
+	if err := s.checkPosition(req.Row, req.Column); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.panel.CursorPosition(int(req.Column), int(req.Row))
+	s.panel.Message(req.Text)
+	return &WriteTextResponse{}, nil
+}
+
+// Clear clears the display
+func (s *Server) Clear(ctx context.Context, req *ClearRequest) (*ClearResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.panel.Clear()
+	return &ClearResponse{}, nil
+}
+
+// MoveCursor moves the cursor
+func (s *Server) MoveCursor(ctx context.Context, req *MoveCursorRequest) (*MoveCursorResponse, error) {
+	if err := s.checkPosition(req.Row, req.Column); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.panel.CursorPosition(int(req.Column), int(req.Row))
+	return &MoveCursorResponse{}, nil
+}
+
+// SetDisplayControl turns the display, cursor and blinking on or off
+func (s *Server) SetDisplayControl(ctx context.Context, req *SetDisplayControlRequest) (*SetDisplayControlResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if req.Display != nil {
+		s.panel.SetDisplay(*req.Display)
+	}
+	if req.Cursor != nil {
+		s.panel.SetCursor(*req.Cursor)
+	}
+	if req.Blink != nil {
+		s.panel.SetBlink(*req.Blink)
+	}
+	return &SetDisplayControlResponse{}, nil
+}
+
+// SetColor sets the RGB LED
+func (s *Server) SetColor(ctx context.Context, req *SetColorRequest) (*SetColorResponse, error) {
+	c := req.GetColor()
+	for _, value := range []int32{c.GetRed(), c.GetGreen(), c.GetBlue()} {
+		if value < 0 || value > 100 {
+			return nil, status.Error(codes.InvalidArgument, "color values must be from 0-100")
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.panel.SetColor(int(c.GetRed()), int(c.GetGreen()), int(c.GetBlue()))
+	return &SetColorResponse{}, nil
+}
+
+// SetBacklight turns the backlight on or off
+func (s *Server) SetBacklight(ctx context.Context, req *SetBacklightRequest) (*SetBacklightResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.panel.SetBacklight(req.On); err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	return &SetBacklightResponse{}, nil
+}
+
+// DefineGlyph defines a custom character
+func (s *Server) DefineGlyph(ctx context.Context, req *DefineGlyphRequest) (*DefineGlyphResponse, error) {
+	if req.Location > 7 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid glyph location %d", req.Location)
+	}
+	if len(req.Pattern) == 0 || len(req.Pattern) > 11 {
+		return nil, status.Error(codes.InvalidArgument, "pattern needs 8 rows, or 11 with the 5x10 font")
+	}
+	for _, row := range req.Pattern {
+		if row > 31 {
+			return nil, status.Error(codes.InvalidArgument, "pattern rows must be from 0-31")
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.panel.CreateChar(byte(req.Location), req.Pattern)
+	return &DefineGlyphResponse{}, nil
+}
+
+// StreamButtons sends an event for every button press and release until
+// the client goes away
+func (s *Server) StreamButtons(req *StreamButtonsRequest, stream Display_StreamButtonsServer) error {
+	interval := s.ButtonInterval
+	if interval <= 0 {
+		interval = 20 * time.Millisecond
+	}
+
+	ctx := stream.Context()
+	for event := range s.panel.WatchButtons(ctx, interval) {
+		err := stream.Send(&ButtonEvent{
+			Button:  charLCDRGBI2C.ButtonName(event.Button),
+			Pressed: event.Pressed,
+			Time:    timestamppb.New(event.Time),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+// GetState returns the size, text, color and backlight of the display
+func (s *Server) GetState(ctx context.Context, req *GetStateRequest) (*State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	columns, lines := s.panel.Size()
+	red, green, blue := s.panel.Color()
+	return &State{
+		Columns:   int32(columns),
+		Lines:     int32(lines),
+		Text:      s.panel.Text(),
+		Color:     &Color{Red: int32(red), Green: int32(green), Blue: int32(blue)},
+		Backlight: s.panel.Backlight(),
+	}, nil
+}
+
+// checkPosition checks that a position is on the display
+func (s *Server) checkPosition(row, column int32) error {
+	columns, lines := s.panel.Size()
+	if row < 0 || int(row) >= lines || column < 0 || int(column) >= columns {
+		return status.Errorf(codes.OutOfRange, "position %d,%d outside the %dx%d display", row, column, columns, lines)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package grpcapi_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jyap808/charLCDRGBI2C"
+	"github.com/jyap808/charLCDRGBI2C/grpcapi"
+	"github.com/jyap808/charLCDRGBI2C/sim"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDefineGlyph(t *testing.T) {
+	lcd, dev := sim.NewLCD(t, charLCDRGBI2C.Geometry16x2, sim.Wiring{})
+	s := grpcapi.NewServer(lcd)
+
+	pattern := []byte{0, 10, 31, 31, 14, 4, 0, 0}
+	if _, err := s.DefineGlyph(context.Background(), &grpcapi.DefineGlyphRequest{Location: 3, Pattern: pattern}); err != nil {
+		t.Fatal(err)
+	}
+	if got := dev.Glyph(0, 3); string(got) != string(pattern) {
+		t.Errorf("glyph 3 is %v, want %v", got, pattern)
+	}
+
+	for _, req := range []*grpcapi.DefineGlyphRequest{
+		{Location: 8, Pattern: pattern},
+		{Location: 3},
+		{Location: 3, Pattern: make([]byte, 12)},
+		{Location: 3, Pattern: []byte{32}},
+	} {
+		if _, err := s.DefineGlyph(context.Background(), req); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("DefineGlyph(%d, %v) returned %v, want %v", req.Location, req.Pattern, err, codes.InvalidArgument)
+		}
+	}
+}
@@ -0,0 +1,406 @@
+// Package httpapi exposes a panel over HTTP with JSON bodies.
+//
+// The endpoints are:
+//
+//	GET  /lines            {"lines": ["Hello", "World"]}
+//	PUT  /lines            {"lines": ["Hello", "World"]}, replaces every line
+//	GET  /lines/{row}      {"text": "Hello"}
+//	PUT  /lines/{row}      {"text": "Hello"}, replaces one line
+//	GET  /color            {"red": 100, "green": 0, "blue": 0, "hex": "#FF0000"}
+//	PUT  /color            {"red": 100, "green": 0, "blue": 0} or {"hex": "#FF0000"}
+//	GET  /backlight        {"on": true}
+//	PUT  /backlight        {"on": true}
+//	PUT  /chars/{location} {"pattern": [0, 10, 31, 31, 14, 4, 0, 0]}
+//	GET  /buttons          Server-Sent Events, one "button" event per press
+//	                       and release: {"button": "select", "pressed": true, ...}
+//
+// Line text is written as HD44780 character codes like Message, padded
+// with spaces or cut to the width of the display. Color values are from
+// 0-100, hex colors are scaled like SetColorRGB.
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jyap808/charLCDRGBI2C"
+)
+
+// Handler serves the HTTP API of a panel
+type Handler struct {
+	ButtonInterval time.Duration // Button polling interval, zero means 20ms
+
+	panel charLCDRGBI2C.Panel
+	mux   *http.ServeMux
+	mu    sync.Mutex // Keeps multi-step updates together
+
+	// Button event streams share one watcher, running while there are any
+	watchMu   sync.Mutex
+	listeners map[chan charLCDRGBI2C.ButtonEvent]bool
+	stopWatch context.CancelFunc
+}
+
+// Line is the body of /lines/{row}
+type Line struct {
+	Text string `json:"text"`
+}
+
+// Lines is the body of /lines
+type Lines struct {
+	Lines []string `json:"lines"`
+}
+
+// Color is the body of /color. Requests set either Hex or the components.
+type Color struct {
+	Red   int    `json:"red"`
+	Green int    `json:"green"`
+	Blue  int    `json:"blue"`
+	Hex   string `json:"hex,omitempty"`
+}
+
+// Backlight is the body of /backlight
+type Backlight struct {
+	On bool `json:"on"`
+}
+
+// Char is the body of /chars/{location}
+type Char struct {
+	Pattern []int `json:"pattern"`
+}
+
+// ButtonEvent is the data of a "button" Server-Sent Event
+type ButtonEvent struct {
+	Button  string    `json:"button"` // Button name, e.g. "select"
+	Pressed bool      `json:"pressed"`
+	Time    time.Time `json:"time"`
+}
+
+// NewHandler returns a handler serving panel. Mount it with
+// http.StripPrefix to serve it below a path.
+func NewHandler(panel charLCDRGBI2C.Panel) *Handler {
+	h := &Handler{panel: panel, mux: http.NewServeMux()}
+	h.mux.HandleFunc("GET /lines", h.getLines)
+	h.mux.HandleFunc("PUT /lines", h.putLines)
+	h.mux.HandleFunc("GET /lines/{row}", h.getLine)
+	h.mux.HandleFunc("PUT /lines/{row}", h.putLine)
+	h.mux.HandleFunc("GET /color", h.getColor)
+	h.mux.HandleFunc("PUT /color", h.putColor)
+	h.mux.HandleFunc("GET /backlight", h.getBacklight)
+	h.mux.HandleFunc("PUT /backlight", h.putBacklight)
+	h.mux.HandleFunc("PUT /chars/{location}", h.putChar)
+	h.mux.HandleFunc("GET /buttons", h.buttons)
+	return h
+}
+
+// ServeHTTP implements http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *Handler) getLines(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, Lines{Lines: h.panel.Text()})
+}
+
+func (h *Handler) putLines(w http.ResponseWriter, r *http.Request) {
+	var body Lines
+	if !readJSON(w, r, &body) {
+		return
+	}
+	_, lines := h.panel.Size()
+	if len(body.Lines) > lines {
+		http.Error(w, fmt.Sprintf("%d lines for a %d line display", len(body.Lines), lines), http.StatusBadRequest)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for row := 0; row < lines; row++ {
+		text := ""
+		if row < len(body.Lines) {
+			text = body.Lines[row]
+		}
+		h.writeLine(row, text)
+	}
+	writeJSON(w, Lines{Lines: h.panel.Text()})
+}
+
+func (h *Handler) getLine(w http.ResponseWriter, r *http.Request) {
+	row, ok := h.row(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, Line{Text: h.line(row)})
+}
+
+func (h *Handler) putLine(w http.ResponseWriter, r *http.Request) {
+	row, ok := h.row(w, r)
+	if !ok {
+		return
+	}
+	var body Line
+	if !readJSON(w, r, &body) {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.writeLine(row, body.Text)
+	writeJSON(w, Line{Text: h.line(row)})
+}
+
+func (h *Handler) getColor(w http.ResponseWriter, r *http.Request) {
+	red, green, blue := h.panel.Color()
+	writeJSON(w, newColor(red, green, blue))
+}
+
+func (h *Handler) putColor(w http.ResponseWriter, r *http.Request) {
+	var body Color
+	if !readJSON(w, r, &body) {
+		return
+	}
+	red, green, blue := body.Red, body.Green, body.Blue
+	if body.Hex != "" {
+		value, err := strconv.ParseUint(strings.TrimPrefix(body.Hex, "#"), 16, 24)
+		if err != nil || len(strings.TrimPrefix(body.Hex, "#")) != 6 {
+			http.Error(w, fmt.Sprintf("invalid hex color %q", body.Hex), http.StatusBadRequest)
+			return
+		}
+		red, green, blue, _ = charLCDRGBI2C.ColorFromRGB(int(value))
+	}
+	for _, value := range []int{red, green, blue} {
+		if value < 0 || value > 100 {
+			http.Error(w, "color values must be from 0-100", http.StatusBadRequest)
+			return
+		}
+	}
+
+	h.panel.SetColor(red, green, blue)
+	writeJSON(w, newColor(red, green, blue))
+}
+
+func (h *Handler) getBacklight(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, Backlight{On: h.panel.Backlight()})
+}
+
+func (h *Handler) putBacklight(w http.ResponseWriter, r *http.Request) {
+	var body Backlight
+	if !readJSON(w, r, &body) {
+		return
+	}
+	if err := h.panel.SetBacklight(body.On); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, body)
+}
+
+func (h *Handler) putChar(w http.ResponseWriter, r *http.Request) {
+	location, err := strconv.Atoi(r.PathValue("location"))
+	if err != nil || location < 0 || location > 7 {
+		http.Error(w, "location must be from 0-7", http.StatusNotFound)
+		return
+	}
+	var body Char
+	if !readJSON(w, r, &body) {
+		return
+	}
+	if len(body.Pattern) == 0 || len(body.Pattern) > 11 {
+		http.Error(w, "pattern needs 8 rows, or 11 with the 5x10 font", http.StatusBadRequest)
+		return
+	}
+
+	pattern := make([]byte, len(body.Pattern))
+	for i, row := range body.Pattern {
+		if row < 0 || row > 0x1F {
+			http.Error(w, "pattern rows must be from 0-31", http.StatusBadRequest)
+			return
+		}
+		pattern[i] = byte(row)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.panel.CreateChar(byte(location), pattern)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// buttons streams button events as Server-Sent Events until the client
+// goes away, or the panel stops sending them
+func (h *Handler) buttons(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	events := h.listen()
+	defer h.unlisten(events)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		var event charLCDRGBI2C.ButtonEvent
+		var ok bool
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok = <-events:
+			if !ok {
+				return
+			}
+		}
+
+		data, err := json.Marshal(ButtonEvent{
+			Button:  charLCDRGBI2C.ButtonName(event.Button),
+			Pressed: event.Pressed,
+			Time:    event.Time,
+		})
+		if err != nil {
+			return
+		}
+		if _, err := fmt.Fprintf(w, "event: button\ndata: %s\n\n", data); err != nil {
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+// listen returns a channel receiving button events until unlisten, or
+// closed when the panel stops sending them. The buttons are watched once for
+// every listener, and only while there are listeners.
+func (h *Handler) listen() chan charLCDRGBI2C.ButtonEvent {
+	events := make(chan charLCDRGBI2C.ButtonEvent, 16)
+
+	h.watchMu.Lock()
+	defer h.watchMu.Unlock()
+	if h.listeners == nil {
+		h.listeners = make(map[chan charLCDRGBI2C.ButtonEvent]bool)
+	}
+	h.listeners[events] = true
+	if h.stopWatch == nil {
+		interval := h.ButtonInterval
+		if interval <= 0 {
+			interval = 20 * time.Millisecond
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		h.stopWatch = cancel
+		go h.fanOut(ctx, h.panel.WatchButtons(ctx, interval))
+	}
+	return events
+}
+
+// unlisten stops sending button events to events
+func (h *Handler) unlisten(events chan charLCDRGBI2C.ButtonEvent) {
+	h.watchMu.Lock()
+	defer h.watchMu.Unlock()
+	delete(h.listeners, events)
+	if len(h.listeners) == 0 && h.stopWatch != nil {
+		h.stopWatch()
+		h.stopWatch = nil
+	}
+}
+
+// fanOut sends the events of a watcher to every listener until ctx is
+// done. Events are dropped for listeners that fall behind. When the watcher
+// stops first, because the panel was closed, the listeners are closed and
+// dropped, the next listen starts another watcher.
+func (h *Handler) fanOut(ctx context.Context, events <-chan charLCDRGBI2C.ButtonEvent) {
+	for event := range events {
+		h.watchMu.Lock()
+		if ctx.Err() == nil {
+			for listener := range h.listeners {
+				select {
+				case listener <- event:
+				default:
+				}
+			}
+		}
+		h.watchMu.Unlock()
+	}
+
+	h.watchMu.Lock()
+	defer h.watchMu.Unlock()
+	if ctx.Err() != nil {
+		// Stopped by unlisten, the listeners belong to the next watcher
+		return
+	}
+	for listener := range h.listeners {
+		close(listener)
+		delete(h.listeners, listener)
+	}
+	h.stopWatch()
+	h.stopWatch = nil
+}
+
+// writeLine replaces the text of a row. h.mu must be held.
+func (h *Handler) writeLine(row int, text string) {
+	columns, _ := h.panel.Size()
+	runes := []rune(text)
+	if len(runes) > columns {
+		runes = runes[:columns]
+	}
+	h.panel.CursorPosition(0, row)
+	h.panel.Message(string(runes) + strings.Repeat(" ", columns-len(runes)))
+}
+
+// line returns the text of a row
+func (h *Handler) line(row int) string {
+	text := h.panel.Text()
+	if row >= len(text) {
+		return ""
+	}
+	return text[row]
+}
+
+// row parses the row of the request path
+func (h *Handler) row(w http.ResponseWriter, r *http.Request) (int, bool) {
+	_, lines := h.panel.Size()
+	row, err := strconv.Atoi(r.PathValue("row"))
+	if err != nil || row < 0 || row >= lines {
+		http.Error(w, fmt.Sprintf("row must be from 0-%d", lines-1), http.StatusNotFound)
+		return 0, false
+	}
+	return row, true
+}
+
+// newColor returns the body of /color
+func newColor(red, green, blue int) Color {
+	return Color{
+		Red:   red,
+		Green: green,
+		Blue:  blue,
+		Hex:   fmt.Sprintf("#%06X", charLCDRGBI2C.RGBFromColor(red, green, blue)),
+	}
+}
+
+// readJSON decodes the request body into v, answering 400 when it is invalid
+func readJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<16))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+			return false
+		}
+		http.Error(w, fmt.Sprintf("invalid JSON body: %v", err), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// writeJSON answers with v as JSON
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,200 @@
+package httpapi_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jyap808/charLCDRGBI2C"
+	"github.com/jyap808/charLCDRGBI2C/httpapi"
+	"github.com/jyap808/charLCDRGBI2C/sim"
+)
+
+// countingPanel counts the button watchers started on it
+type countingPanel struct {
+	*charLCDRGBI2C.CharLCDRGBI2C
+	watchers atomic.Int32
+}
+
+func (p *countingPanel) WatchButtons(ctx context.Context, interval time.Duration) <-chan charLCDRGBI2C.ButtonEvent {
+	p.watchers.Add(1)
+	return p.CharLCDRGBI2C.WatchButtons(ctx, interval)
+}
+
+// newServer serves the API of a simulated board
+func newServer(t *testing.T) (*httptest.Server, *countingPanel, *sim.Device) {
+	t.Helper()
+	lcd, dev := sim.NewLCD(t, charLCDRGBI2C.Geometry16x2, sim.Wiring{})
+	panel := &countingPanel{CharLCDRGBI2C: lcd}
+	h := httpapi.NewHandler(panel)
+	h.ButtonInterval = 5 * time.Millisecond
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	return srv, panel, dev
+}
+
+// do makes a request with a JSON body and decodes the JSON reply into v
+func do(t *testing.T, method, url, body string, v any) int {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if v != nil && resp.StatusCode == http.StatusOK {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func TestLines(t *testing.T) {
+	srv, _, dev := newServer(t)
+
+	var lines httpapi.Lines
+	if code := do(t, "PUT", srv.URL+"/lines", `{"lines": ["Hello", "World"]}`, &lines); code != http.StatusOK {
+		t.Fatalf("PUT /lines: %d", code)
+	}
+	if got := string(dev.Codes()[1]); got != "World           " {
+		t.Errorf("line 1 shows %q", got)
+	}
+
+	var line httpapi.Line
+	do(t, "PUT", srv.URL+"/lines/0", `{"text": "Build passed"}`, nil)
+	do(t, "GET", srv.URL+"/lines/0", "", &line)
+	if line.Text != "Build passed    " {
+		t.Errorf("GET /lines/0 = %q", line.Text)
+	}
+	if code := do(t, "GET", srv.URL+"/lines/2", "", nil); code != http.StatusNotFound {
+		t.Errorf("GET /lines/2: %d, want %d", code, http.StatusNotFound)
+	}
+}
+
+func TestColor(t *testing.T) {
+	srv, _, dev := newServer(t)
+
+	var color httpapi.Color
+	if code := do(t, "PUT", srv.URL+"/color", `{"hex": "#00FF00"}`, &color); code != http.StatusOK {
+		t.Fatalf("PUT /color: %d", code)
+	}
+	want := httpapi.Color{Red: 0, Green: 100, Blue: 0, Hex: "#00FF00"}
+	if color != want {
+		t.Errorf("PUT /color = %+v, want %+v", color, want)
+	}
+	if red, green, blue := dev.LED(); red || !green || blue {
+		t.Errorf("LED red %v green %v blue %v, want green", red, green, blue)
+	}
+
+	for _, body := range []string{`{"hex": "#00FF0"}`, `{"red": 101}`, `{"blue": -1}`} {
+		if code := do(t, "PUT", srv.URL+"/color", body, nil); code != http.StatusBadRequest {
+			t.Errorf("PUT /color %s: %d, want %d", body, code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestChars(t *testing.T) {
+	srv, _, dev := newServer(t)
+
+	if code := do(t, "PUT", srv.URL+"/chars/3", `{"pattern": [0, 10, 31, 31, 14, 4, 0, 0]}`, nil); code != http.StatusNoContent {
+		t.Fatalf("PUT /chars/3: %d", code)
+	}
+	if got := dev.Glyph(0, 3); string(got) != "\x00\x0a\x1f\x1f\x0e\x04\x00\x00" {
+		t.Errorf("glyph 3 is %v", got)
+	}
+	if code := do(t, "PUT", srv.URL+"/chars/3", `{"pattern": [32]}`, nil); code != http.StatusBadRequest {
+		t.Errorf("PUT /chars/3 with row 32: %d, want %d", code, http.StatusBadRequest)
+	}
+	if code := do(t, "PUT", srv.URL+"/chars/8", `{"pattern": [0]}`, nil); code != http.StatusNotFound {
+		t.Errorf("PUT /chars/8: %d, want %d", code, http.StatusNotFound)
+	}
+}
+
+func TestButtons(t *testing.T) {
+	srv, panel, dev := newServer(t)
+
+	// Several streams share one watcher
+	var streams []*bufio.Reader
+	for range 3 {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, "GET", srv.URL+"/buttons", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		streams = append(streams, bufio.NewReader(resp.Body))
+	}
+	if n := panel.watchers.Load(); n != 1 {
+		t.Errorf("%d button watchers for 3 streams, want 1", n)
+	}
+
+	dev.Press(charLCDRGBI2C.SelectButton)
+	for i, stream := range streams {
+		var event httpapi.ButtonEvent
+		for {
+			line, err := stream.ReadString('\n')
+			if err != nil {
+				t.Fatalf("stream %d: %v", i, err)
+			}
+			if data, ok := strings.CutPrefix(line, "data: "); ok {
+				if err := json.Unmarshal([]byte(data), &event); err != nil {
+					t.Fatal(err)
+				}
+				break
+			}
+		}
+		if event.Button != "select" || !event.Pressed {
+			t.Errorf("stream %d got %+v, want select pressed", i, event)
+		}
+	}
+}
+
+func TestButtonsEndWithPanel(t *testing.T) {
+	srv, panel, _ := newServer(t)
+	resp, err := http.Get(srv.URL + "/buttons")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	panel.Close(charLCDRGBI2C.CloseOptions{})
+	ended := make(chan error)
+	go func() {
+		_, err := io.Copy(io.Discard, resp.Body)
+		ended <- err
+	}()
+	select {
+	case err := <-ended:
+		if err != nil {
+			t.Error(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("stream still open after the panel was closed")
+	}
+
+	// Streams opened afterwards end right away
+	resp, err = http.Get(srv.URL + "/buttons")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if _, err := io.Copy(io.Discard, resp.Body); err != nil {
+		t.Error(err)
+	}
+}
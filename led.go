@@ -1,13 +1,18 @@
 package charLCDRGBI2C
 
 import (
-	"log"
-
-	"github.com/googolgl/go-mcp23017"
+	"fmt"
 )
 
 // SetColor sets the RGB LED color (values from 0-100)
 func (lcd *CharLCDRGBI2C) SetColor(red, green, blue int) {
+	lcd.lock()
+	defer lcd.unlock()
+	lcd.setColor(red, green, blue)
+}
+
+// setColor sets the RGB LED color (values from 0-100)
+func (lcd *CharLCDRGBI2C) setColor(red, green, blue int) {
 	lcd.colorValue = [3]int{red, green, blue}
 
 	// We need to invert the values as the Python code does (map 0-100 to on/off)
@@ -16,28 +21,55 @@ func (lcd *CharLCDRGBI2C) SetColor(red, green, blue int) {
 
 	// Update each LED
 	values := [3]int{red, green, blue}
-	pins := [3]string{RedPin, GreenPin, BluePin}
 
 	for i, value := range values {
-		if value > 1 {
+		pin := lcd.rgb[i]
+		switch {
+		case pin == "":
+			// Not wired
+		case value > 1:
 			// Any value > 1 turns LED on (inverse of Python logic)
-			lcd.mcp.Set(mcp23017.Pins{pins[i]}).LOW() // LOW = on for common anode RGB LED
-		} else {
-			lcd.mcp.Set(mcp23017.Pins{pins[i]}).HIGH() // HIGH = off
+			lcd.track(lcd.pins.Low(pin)) // LOW = on for common anode RGB LED
+		default:
+			lcd.track(lcd.pins.High(pin)) // HIGH = off
 		}
 	}
 }
 
-// SetColorRGB sets the RGB LED color using a 24-bit RGB integer
+// SetColorRGB sets the RGB LED color using a 24-bit RGB integer. Values
+// that are negative or wider than 24 bits leave the LED unchanged.
 func (lcd *CharLCDRGBI2C) SetColorRGB(colorInt int) {
+	lcd.lock()
+	defer lcd.unlock()
+	lcd.setColorRGB(colorInt)
+}
+
+// setColorRGB sets the RGB LED color using a 24-bit RGB integer
+func (lcd *CharLCDRGBI2C) setColorRGB(colorInt int) {
+	if red, green, blue, err := ColorFromRGB(colorInt); err == nil {
+		lcd.setColor(red, green, blue)
+	}
+}
+
+// ColorFromRGB converts a 24-bit RGB integer to the 0-100 values of
+// SetColor, like SetColorRGB does
+func ColorFromRGB(colorInt int) (red, green, blue int, err error) {
 	if colorInt>>24 != 0 {
-		log.Fatal("Integer color value must be positive and 24 bits max")
+		return 0, 0, 0, fmt.Errorf("integer color value must be positive and 24 bits max, got %#x", colorInt)
 	}
 
 	// Extract RGB components and convert to 0-100 scale
 	r := float64(colorInt>>16) / 2.55
 	g := float64((colorInt>>8)&0xFF) / 2.55
 	b := float64(colorInt&0xFF) / 2.55
+	return int(r), int(g), int(b), nil
+}
 
-	lcd.SetColor(int(r), int(g), int(b))
+// RGBFromColor converts 0-100 values of SetColor to a 24-bit RGB integer,
+// values outside 0-100 are clamped
+func RGBFromColor(red, green, blue int) int {
+	scale := func(value int) int {
+		return min(max(value, 0), 100) * 255 / 100
+	}
+	return scale(red)<<16 | scale(green)<<8 | scale(blue)
 }
@@ -0,0 +1,394 @@
+// Package mqttbridge connects a panel to an MQTT broker.
+//
+// With the default prefix "charlcd" the topics are:
+//
+//	charlcd/status             "online" or "offline", retained
+//	charlcd/text               the lines of the display joined by "\n", retained
+//	charlcd/text/set           text to show, "\n" starts the next line
+//	charlcd/color              the RGB LED as "red,green,blue" from 0-255, retained
+//	charlcd/color/set          "red,green,blue" from 0-255 or "#RRGGBB"
+//	charlcd/backlight          "ON" or "OFF", retained
+//	charlcd/backlight/set      "ON" or "OFF"
+//	charlcd/led                "ON" while the RGB LED is lit, "OFF" when it is black, retained
+//	charlcd/led/set            "ON" lights the LED in its last color, "OFF" turns it black
+//	charlcd/button/{name}      "ON" while pressed, "OFF" when released, retained
+//
+// Home Assistant discovery payloads publish the display as a text entity,
+// the backlight as a switch, the RGB LED as a light and the buttons as five
+// binary sensors. Set the client's will to "offline" on Config.StatusTopic so Home
+// Assistant sees the bridge go away.
+package mqttbridge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/jyap808/charLCDRGBI2C"
+)
+
+// Config selects the topics and Home Assistant names of a bridge
+type Config struct {
+	Prefix          string        // Topic prefix, defaults to "charlcd"
+	NodeID          string        // Home Assistant node and unique id prefix, defaults to "charlcd"
+	Name            string        // Home Assistant device name, defaults to "Character LCD"
+	DiscoveryPrefix string        // Home Assistant discovery prefix, defaults to "homeassistant", "-" disables discovery
+	QoS             byte          // QoS of publishes and subscriptions
+	ButtonInterval  time.Duration // Button polling interval, zero means 20ms
+	StateInterval   time.Duration // Interval display state changes are looked for, zero means 1s
+}
+
+// Bridge publishes the buttons and display state of a panel and runs the
+// display commands it receives
+type Bridge struct {
+	client mqtt.Client
+	panel  charLCDRGBI2C.Panel
+	cfg    Config
+	state  state  // Last published state
+	color  [3]int // Color the LED goes back to when turned on
+}
+
+// command is a message received on a command topic
+type command struct {
+	topic   string
+	payload string
+}
+
+// state is the display state published to the broker
+type state struct {
+	text      string
+	color     string
+	backlight string
+	led       string
+}
+
+// New returns a bridge between a connected client and panel
+func New(client mqtt.Client, panel charLCDRGBI2C.Panel, cfg Config) *Bridge {
+	if cfg.Prefix == "" {
+		cfg.Prefix = "charlcd"
+	}
+	if cfg.NodeID == "" {
+		cfg.NodeID = "charlcd"
+	}
+	if cfg.Name == "" {
+		cfg.Name = "Character LCD"
+	}
+	if cfg.DiscoveryPrefix == "" {
+		cfg.DiscoveryPrefix = "homeassistant"
+	}
+	if cfg.ButtonInterval <= 0 {
+		cfg.ButtonInterval = 20 * time.Millisecond
+	}
+	if cfg.StateInterval <= 0 {
+		cfg.StateInterval = time.Second
+	}
+	return &Bridge{client: client, panel: panel, cfg: cfg, color: [3]int{100, 100, 100}}
+}
+
+// StatusTopic returns the availability topic, for the client's will
+func (c Config) StatusTopic() string {
+	if c.Prefix == "" {
+		return "charlcd/status"
+	}
+	return c.Prefix + "/status"
+}
+
+// Run publishes discovery and state, subscribes to the command topics and
+// forwards button events until ctx is done. It publishes "offline" before
+// returning.
+func (b *Bridge) Run(ctx context.Context) error {
+	if b.cfg.DiscoveryPrefix != "-" {
+		if err := b.publishDiscovery(); err != nil {
+			return err
+		}
+	}
+
+	commands := map[string]func(payload string) error{
+		b.topic("text/set"):      b.setText,
+		b.topic("color/set"):     b.setColor,
+		b.topic("backlight/set"): b.setBacklight,
+		b.topic("led/set"):       b.setLED,
+	}
+	// Commands run on this goroutine, paho handlers must not block
+	received := make(chan command, 16)
+	for topic := range commands {
+		token := b.client.Subscribe(topic, b.cfg.QoS, func(_ mqtt.Client, msg mqtt.Message) {
+			select {
+			case received <- command{topic: msg.Topic(), payload: string(msg.Payload())}:
+			default:
+				log.Printf("Dropping command on %s", msg.Topic())
+			}
+		})
+		if err := wait(token); err != nil {
+			return fmt.Errorf("subscribing to %s: %w", topic, err)
+		}
+	}
+	defer func() {
+		topics := make([]string, 0, len(commands))
+		for topic := range commands {
+			topics = append(topics, topic)
+		}
+		wait(b.client.Unsubscribe(topics...))
+	}()
+
+	for _, button := range charLCDRGBI2C.Buttons {
+		b.publish("button/"+charLCDRGBI2C.ButtonName(button), "OFF")
+	}
+	b.publishState(true)
+	if err := b.publish("status", "online"); err != nil {
+		return err
+	}
+
+	events := b.panel.WatchButtons(ctx, b.cfg.ButtonInterval)
+	ticker := time.NewTicker(b.cfg.StateInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			b.publish("status", "offline")
+			return ctx.Err()
+		case event, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+			payload := "OFF"
+			if event.Pressed {
+				payload = "ON"
+			}
+			b.publish("button/"+charLCDRGBI2C.ButtonName(event.Button), payload)
+		case cmd := <-received:
+			if err := commands[cmd.topic](cmd.payload); err != nil {
+				log.Printf("Error handling %s: %v", cmd.topic, err)
+			}
+		case <-ticker.C:
+			// Pick up changes made by other users of the panel
+			b.publishState(false)
+		}
+	}
+}
+
+// setText shows text, one line of the payload per display line
+func (b *Bridge) setText(payload string) error {
+	columns, lines := b.panel.Size()
+	text := strings.Split(payload, "\n")
+	if len(text) > lines {
+		return fmt.Errorf("%d lines for a %d line display", len(text), lines)
+	}
+
+	for row := 0; row < lines; row++ {
+		line := ""
+		if row < len(text) {
+			line = text[row]
+		}
+		runes := []rune(line)
+		if len(runes) > columns {
+			runes = runes[:columns]
+		}
+		b.panel.CursorPosition(0, row)
+		b.panel.Message(string(runes) + strings.Repeat(" ", columns-len(runes)))
+	}
+	b.publishState(false)
+	return nil
+}
+
+// setColor sets the RGB LED from "red,green,blue" (0-255) or "#RRGGBB"
+func (b *Bridge) setColor(payload string) error {
+	var rgb int
+	payload = strings.TrimSpace(payload)
+	if hex, ok := strings.CutPrefix(payload, "#"); ok {
+		value, err := strconv.ParseUint(hex, 16, 24)
+		if err != nil || len(hex) != 6 {
+			return fmt.Errorf("invalid color %q", payload)
+		}
+		rgb = int(value)
+	} else {
+		fields := strings.Split(payload, ",")
+		if len(fields) != 3 {
+			return fmt.Errorf("invalid color %q", payload)
+		}
+		for _, field := range fields {
+			value, err := strconv.Atoi(strings.TrimSpace(field))
+			if err != nil || value < 0 || value > 255 {
+				return fmt.Errorf("invalid color %q", payload)
+			}
+			rgb = rgb<<8 | value
+		}
+	}
+
+	// The LED takes values from 0-100, scaled like SetColorRGB
+	red, green, blue, err := charLCDRGBI2C.ColorFromRGB(rgb)
+	if err != nil {
+		return err
+	}
+	b.panel.SetColor(red, green, blue)
+	b.publishState(false)
+	return nil
+}
+
+// setBacklight turns the backlight on or off from "ON" or "OFF"
+func (b *Bridge) setBacklight(payload string) error {
+	on, err := onOff(payload)
+	if err != nil {
+		return err
+	}
+	if err := b.panel.SetBacklight(on); err != nil {
+		return err
+	}
+	b.publishState(false)
+	return nil
+}
+
+// setLED turns the RGB LED on in its last color or off from "ON" or "OFF"
+func (b *Bridge) setLED(payload string) error {
+	on, err := onOff(payload)
+	if err != nil {
+		return err
+	}
+
+	red, green, blue := b.panel.Color()
+	lit := red != 0 || green != 0 || blue != 0
+	switch {
+	case on && !lit:
+		b.panel.SetColor(b.color[0], b.color[1], b.color[2])
+	case !on && lit:
+		b.color = [3]int{red, green, blue}
+		b.panel.SetColor(0, 0, 0)
+	}
+	b.publishState(false)
+	return nil
+}
+
+// onOff parses an "ON" or "OFF" payload
+func onOff(payload string) (bool, error) {
+	switch strings.ToUpper(strings.TrimSpace(payload)) {
+	case "ON":
+		return true, nil
+	case "OFF":
+		return false, nil
+	}
+	return false, fmt.Errorf("expected ON or OFF, got %q", payload)
+}
+
+// publishState publishes the parts of the display state that changed, or
+// all of them when force is set
+func (b *Bridge) publishState(force bool) {
+	var lines []string
+	for _, line := range b.panel.Text() {
+		lines = append(lines, strings.TrimRight(line, " "))
+	}
+	rgb := charLCDRGBI2C.RGBFromColor(b.panel.Color())
+	backlight := "OFF"
+	if b.panel.Backlight() {
+		backlight = "ON"
+	}
+	led := "OFF"
+	if rgb != 0 {
+		led = "ON"
+	}
+	current := state{
+		text:      strings.Join(lines, "\n"),
+		color:     fmt.Sprintf("%d,%d,%d", rgb>>16, rgb>>8&0xFF, rgb&0xFF),
+		backlight: backlight,
+		led:       led,
+	}
+
+	if force || current.text != b.state.text {
+		b.publish("text", current.text)
+	}
+	if force || current.color != b.state.color {
+		b.publish("color", current.color)
+	}
+	if force || current.backlight != b.state.backlight {
+		b.publish("backlight", current.backlight)
+	}
+	if force || current.led != b.state.led {
+		b.publish("led", current.led)
+	}
+	b.state = current
+}
+
+// publishDiscovery publishes the Home Assistant discovery payloads
+func (b *Bridge) publishDiscovery() error {
+	device := map[string]any{
+		"identifiers":  []string{b.cfg.NodeID},
+		"name":         b.cfg.Name,
+		"model":        "RGB1602",
+		"manufacturer": "charLCDRGBI2C",
+	}
+	entity := func(key string, config map[string]any) map[string]any {
+		config["unique_id"] = b.cfg.NodeID + "_" + key
+		config["object_id"] = b.cfg.NodeID + "_" + key
+		config["availability_topic"] = b.topic("status")
+		config["device"] = device
+		return config
+	}
+
+	columns, lines := b.panel.Size()
+	configs := map[string]map[string]any{
+		"text/text": entity("text", map[string]any{
+			"name":          "Text",
+			"state_topic":   b.topic("text"),
+			"command_topic": b.topic("text/set"),
+			"max":           min(columns*lines+lines-1, 255),
+		}),
+		"switch/backlight": entity("backlight", map[string]any{
+			"name":          "Backlight",
+			"state_topic":   b.topic("backlight"),
+			"command_topic": b.topic("backlight/set"),
+		}),
+		"light/led": entity("led", map[string]any{
+			"name":              "LED",
+			"state_topic":       b.topic("led"),
+			"command_topic":     b.topic("led/set"),
+			"rgb_state_topic":   b.topic("color"),
+			"rgb_command_topic": b.topic("color/set"),
+		}),
+	}
+	for _, button := range charLCDRGBI2C.Buttons {
+		name := charLCDRGBI2C.ButtonName(button)
+		configs["binary_sensor/"+name] = entity(name, map[string]any{
+			"name":        strings.ToUpper(name[:1]) + name[1:] + " button",
+			"state_topic": b.topic("button/" + name),
+		})
+	}
+
+	for key, config := range configs {
+		component, object, _ := strings.Cut(key, "/")
+		payload, err := json.Marshal(config)
+		if err != nil {
+			return err
+		}
+		topic := fmt.Sprintf("%s/%s/%s/%s/config", b.cfg.DiscoveryPrefix, component, b.cfg.NodeID, object)
+		if err := wait(b.client.Publish(topic, b.cfg.QoS, true, payload)); err != nil {
+			return fmt.Errorf("publishing %s: %w", topic, err)
+		}
+	}
+	return nil
+}
+
+// publish publishes a retained payload to a topic below the prefix
+func (b *Bridge) publish(topic, payload string) error {
+	topic = b.topic(topic)
+	if err := wait(b.client.Publish(topic, b.cfg.QoS, true, payload)); err != nil {
+		log.Printf("Error publishing %s: %v", topic, err)
+		return err
+	}
+	return nil
+}
+
+// topic returns a topic below the prefix
+func (b *Bridge) topic(name string) string {
+	return b.cfg.Prefix + "/" + name
+}
+
+// wait waits for a token and returns its error
+func wait(token mqtt.Token) error {
+	token.Wait()
+	return token.Error()
+}
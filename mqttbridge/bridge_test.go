@@ -0,0 +1,207 @@
+package mqttbridge_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/jyap808/charLCDRGBI2C"
+	"github.com/jyap808/charLCDRGBI2C/mqttbridge"
+	"github.com/jyap808/charLCDRGBI2C/sim"
+	broker "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/hooks/auth"
+	"github.com/mochi-mqtt/server/v2/listeners"
+)
+
+// observer keeps the last payload seen on every topic
+type observer struct {
+	mu       sync.Mutex
+	payloads map[string]string
+}
+
+func (o *observer) last(topic string) (string, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	payload, ok := o.payloads[topic]
+	return payload, ok
+}
+
+// await waits for want to be the last payload on topic
+func (o *observer) await(t *testing.T, topic, want string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		got, ok := o.last(topic)
+		if ok && got == want {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%s is %q, want %q", topic, got, want)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// startBroker runs an MQTT broker on a local port and returns its URL
+func startBroker(t *testing.T) string {
+	t.Helper()
+	server := broker.New(&broker.Options{Logger: slog.New(slog.NewTextHandler(io.Discard, nil))})
+	if err := server.AddHook(new(auth.AllowHook), nil); err != nil {
+		t.Fatal(err)
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := server.AddListener(listeners.NewNet("test", ln)); err != nil {
+		t.Fatal(err)
+	}
+	if err := server.Serve(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { server.Close() })
+	return "tcp://" + ln.Addr().String()
+}
+
+// connect connects a client to the broker at url
+func connect(t *testing.T, url, id string) mqtt.Client {
+	t.Helper()
+	client := mqtt.NewClient(mqtt.NewClientOptions().AddBroker(url).SetClientID(id))
+	if token := client.Connect(); token.Wait() && token.Error() != nil {
+		t.Fatal(token.Error())
+	}
+	t.Cleanup(func() { client.Disconnect(0) })
+	return client
+}
+
+// runBridge runs a bridge to a simulated board and returns the board, a
+// client to send commands with and an observer of everything published
+func runBridge(t *testing.T) (*sim.Device, mqtt.Client, *observer) {
+	t.Helper()
+	url := startBroker(t)
+
+	o := &observer{payloads: map[string]string{}}
+	client := connect(t, url, "test")
+	token := client.Subscribe("#", 1, func(_ mqtt.Client, msg mqtt.Message) {
+		o.mu.Lock()
+		defer o.mu.Unlock()
+		o.payloads[msg.Topic()] = string(msg.Payload())
+	})
+	if token.Wait() && token.Error() != nil {
+		t.Fatal(token.Error())
+	}
+
+	lcd, dev := sim.NewLCD(t, charLCDRGBI2C.Geometry16x2, sim.Wiring{})
+	bridge := mqttbridge.New(connect(t, url, "bridge"), lcd, mqttbridge.Config{
+		ButtonInterval: 5 * time.Millisecond,
+		StateInterval:  10 * time.Millisecond,
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		bridge.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	o.await(t, "charlcd/status", "online")
+	return dev, client, o
+}
+
+// send publishes a command
+func send(t *testing.T, client mqtt.Client, topic, payload string) {
+	t.Helper()
+	if token := client.Publish(topic, 1, false, payload); token.Wait() && token.Error() != nil {
+		t.Fatal(token.Error())
+	}
+}
+
+func TestDiscovery(t *testing.T) {
+	_, _, o := runBridge(t)
+
+	tests := []struct {
+		topic, name, state, command string
+	}{
+		{"homeassistant/switch/charlcd/backlight/config", "Backlight", "charlcd/backlight", "charlcd/backlight/set"},
+		{"homeassistant/light/charlcd/led/config", "LED", "charlcd/led", "charlcd/led/set"},
+		{"homeassistant/text/charlcd/text/config", "Text", "charlcd/text", "charlcd/text/set"},
+	}
+	for _, tt := range tests {
+		payload, ok := o.last(tt.topic)
+		if !ok {
+			t.Errorf("nothing published on %s", tt.topic)
+			continue
+		}
+		var config struct {
+			Name         string `json:"name"`
+			StateTopic   string `json:"state_topic"`
+			CommandTopic string `json:"command_topic"`
+		}
+		if err := json.Unmarshal([]byte(payload), &config); err != nil {
+			t.Fatal(err)
+		}
+		if config.Name != tt.name || config.StateTopic != tt.state || config.CommandTopic != tt.command {
+			t.Errorf("%s is %+v, want name %q state %s command %s", tt.topic, config, tt.name, tt.state, tt.command)
+		}
+	}
+}
+
+func TestText(t *testing.T) {
+	dev, client, o := runBridge(t)
+
+	send(t, client, "charlcd/text/set", "Hello\nWorld")
+	o.await(t, "charlcd/text", "Hello\nWorld")
+	if got := string(dev.Codes()[1]); got != "World           " {
+		t.Errorf("line 1 shows %q", got)
+	}
+}
+
+func TestLEDAndBacklight(t *testing.T) {
+	dev, client, o := runBridge(t)
+
+	send(t, client, "charlcd/color/set", "#00FF00")
+	o.await(t, "charlcd/color", "0,255,0")
+	o.await(t, "charlcd/led", "ON")
+
+	// The LED turns off and back on in its color, the backlight stays on
+	send(t, client, "charlcd/led/set", "OFF")
+	o.await(t, "charlcd/led", "OFF")
+	o.await(t, "charlcd/color", "0,0,0")
+	if !dev.Backlight() {
+		t.Error("turning the LED off turned the backlight off")
+	}
+	send(t, client, "charlcd/led/set", "ON")
+	o.await(t, "charlcd/color", "0,255,0")
+	if red, green, blue := dev.LED(); red || !green || blue {
+		t.Errorf("LED red %v green %v blue %v, want green", red, green, blue)
+	}
+
+	// The backlight turns off without touching the LED
+	send(t, client, "charlcd/backlight/set", "OFF")
+	o.await(t, "charlcd/backlight", "OFF")
+	if dev.Backlight() {
+		t.Error("backlight is still on")
+	}
+	if payload, _ := o.last("charlcd/led"); payload != "ON" {
+		t.Errorf("turning the backlight off turned the LED %s", payload)
+	}
+}
+
+func TestButtons(t *testing.T) {
+	dev, _, o := runBridge(t)
+
+	o.await(t, "charlcd/button/select", "OFF")
+	dev.Press(charLCDRGBI2C.SelectButton)
+	o.await(t, "charlcd/button/select", "ON")
+	dev.Release(charLCDRGBI2C.SelectButton)
+	o.await(t, "charlcd/button/select", "OFF")
+}
@@ -0,0 +1,21 @@
+module github.com/jyap808/charLCDRGBI2C/mqttbridge
+
+go 1.23.6
+
+require (
+	github.com/eclipse/paho.mqtt.golang v1.5.0
+	github.com/jyap808/charLCDRGBI2C v0.1.0
+	github.com/mochi-mqtt/server/v2 v2.6.6
+)
+
+require (
+	github.com/googolgl/go-i2c v0.1.1 // indirect
+	github.com/googolgl/go-mcp23017 v0.0.0-20210225115400-eb8b77d91034 // indirect
+	github.com/gorilla/websocket v1.5.3 // indirect
+	github.com/rs/xid v1.4.0 // indirect
+	github.com/sirupsen/logrus v1.8.1 // indirect
+	golang.org/x/net v0.41.0 // indirect
+	golang.org/x/sync v0.15.0 // indirect
+	golang.org/x/sys v0.33.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
@@ -0,0 +1,61 @@
+package charLCDRGBI2C
+
+// Option configures optional hardware features of the LCD
+type Option func(*CharLCDRGBI2C)
+
+// WithSecondEnablePin sets the enable pin of the second HD44780 controller
+// on dual-controller panels such as 40x4. Both controllers share the RS,
+// RW and data pins.
+func WithSecondEnablePin(pin string) Option {
+	return func(lcd *CharLCDRGBI2C) {
+		lcd.enablePins = append(lcd.enablePins[:1], pin)
+	}
+}
+
+// With8BitDataBus drives the LCD over an 8-bit data bus with the given
+// D0-D7 pins, sending every byte in one transfer instead of two nibbles.
+// Boards typically wire the data bus to a full MCP23017 port.
+func With8BitDataBus(pins [8]string) Option {
+	return func(lcd *CharLCDRGBI2C) {
+		lcd.dataPins = pins[:]
+	}
+}
+
+// WithButtons sets which of the on-board Buttons are wired, by default all
+// of them. Buttons left out are not set up and never read as pressed, which
+// frees their port A pins, e.g. for an 8-bit data bus or a second enable
+// pin.
+func WithButtons(buttons ...string) Option {
+	return func(lcd *CharLCDRGBI2C) {
+		lcd.buttons = buttons
+	}
+}
+
+// WithLEDPins sets the pins of the RGB LED and the backlight, by default
+// RedPin, GreenPin, BluePin and BacklightPin. An empty pin is not wired.
+func WithLEDPins(red, green, blue, backlight string) Option {
+	return func(lcd *CharLCDRGBI2C) {
+		lcd.rgb = [3]string{red, green, blue}
+		lcd.backlightPin = backlight
+	}
+}
+
+// WithFont5x10 selects the 5x10 dot font, only available on 1-line panels.
+// Up to four custom characters of 11 rows each can be created in this mode,
+// each shown by two character codes, see CreateChar.
+func WithFont5x10() Option {
+	return func(lcd *CharLCDRGBI2C) {
+		lcd.font5x10 = true
+	}
+}
+
+// WithStateFile keeps the display contents across process restarts. The
+// state is saved to path a second after it changes and on Close, and the
+// next New shows it again: right away when the board kept its pin setup and
+// so the LCD its contents, otherwise restored after the usual
+// initialization. See SaveState.
+func WithStateFile(path string) Option {
+	return func(lcd *CharLCDRGBI2C) {
+		lcd.stateFile = path
+	}
+}
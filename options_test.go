@@ -0,0 +1,67 @@
+package charLCDRGBI2C_test
+
+import (
+	"testing"
+
+	"github.com/jyap808/charLCDRGBI2C"
+	"github.com/jyap808/charLCDRGBI2C/sim"
+)
+
+// portA is a full MCP23017 port, D0-D7 of an 8-bit data bus
+var portA = [8]string{"A0", "A1", "A2", "A3", "A4", "A5", "A6", "A7"}
+
+func TestPinConflicts(t *testing.T) {
+	tests := []struct {
+		name     string
+		geometry charLCDRGBI2C.Geometry
+		opts     []charLCDRGBI2C.Option
+	}{
+		{"8-bit bus on the buttons", charLCDRGBI2C.Geometry16x2, []charLCDRGBI2C.Option{
+			charLCDRGBI2C.With8BitDataBus(portA),
+		}},
+		{"8-bit bus on the LED", charLCDRGBI2C.Geometry16x2, []charLCDRGBI2C.Option{
+			charLCDRGBI2C.With8BitDataBus(portA),
+			charLCDRGBI2C.WithButtons(),
+		}},
+		{"invalid enable pin", charLCDRGBI2C.Geometry40x4, []charLCDRGBI2C.Option{
+			charLCDRGBI2C.WithSecondEnablePin("C9"),
+		}},
+		{"enable pin on a button", charLCDRGBI2C.Geometry40x4, []charLCDRGBI2C.Option{
+			charLCDRGBI2C.WithSecondEnablePin(charLCDRGBI2C.SelectButton),
+		}},
+		{"enable pin on the backlight", charLCDRGBI2C.Geometry40x4, []charLCDRGBI2C.Option{
+			charLCDRGBI2C.WithSecondEnablePin(charLCDRGBI2C.BacklightPin),
+		}},
+		{"LED on a data pin", charLCDRGBI2C.Geometry16x2, []charLCDRGBI2C.Option{
+			charLCDRGBI2C.WithLEDPins(charLCDRGBI2C.LcdD4Pin, "", "", ""),
+		}},
+		{"unknown button", charLCDRGBI2C.Geometry16x2, []charLCDRGBI2C.Option{
+			charLCDRGBI2C.WithButtons("B9"),
+		}},
+	}
+	for _, tt := range tests {
+		dev := sim.New(tt.geometry, sim.Wiring{})
+		if _, err := charLCDRGBI2C.NewWithDriver(dev, tt.geometry, tt.opts...); err == nil {
+			t.Errorf("%s: no error", tt.name)
+		}
+	}
+}
+
+func TestEightBitDataBus(t *testing.T) {
+	lcd, dev := sim.NewLCD(t, charLCDRGBI2C.Geometry16x2, sim.Wiring{DataPins: portA[:]},
+		charLCDRGBI2C.With8BitDataBus(portA),
+		charLCDRGBI2C.WithButtons(),
+		charLCDRGBI2C.WithLEDPins("", "", charLCDRGBI2C.BluePin, ""),
+	)
+	lcd.Message("Hello\nWorld")
+
+	want := []string{"Hello           ", "World           "}
+	for row, line := range dev.Codes() {
+		if string(line) != want[row] {
+			t.Errorf("line %d shows %q, want %q", row, line, want[row])
+		}
+	}
+	if lcd.IsButtonPressed(charLCDRGBI2C.SelectButton) {
+		t.Error("SelectButton pressed, it is not wired")
+	}
+}
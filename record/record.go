@@ -0,0 +1,259 @@
+// Package record logs the pin operations of the LCD driver to a compact
+// file and replays them into the simulator, showing exactly what the panel
+// showed at any moment of the recording:
+//
+//	f, err := os.Create("lcd.rec")
+//	rec, err := record.NewRecorder(driver, f)
+//	lcd, err := charLCDRGBI2C.NewWithDriver(rec, geometry)
+//	...
+//	rec.Close()
+//
+// Recordings replayed with Replay, or the lcdreplay command, also make
+// golden files for regression tests of what the driver sends.
+package record
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+	"strings"
+	"time"
+)
+
+// magic starts every recording, the last byte is the format version
+var magic = []byte("LCDREC\x00\x01")
+
+// Op is a pin operation
+type Op byte
+
+const (
+	OpOutput   Op = iota + 1 // Pins made outputs
+	OpInput                  // Pins made inputs
+	OpHigh                   // Pins driven high
+	OpLow                    // Pins driven low
+	OpPullUp                 // Pull-ups enabled
+	OpPullDown               // Pull-ups disabled
+	OpRead                   // Pin levels read
+	OpOutputs                // Pin directions read
+	OpReset                  // Expander set up from scratch
+
+	opMax = OpReset
+)
+
+// errorFlag marks an operation that failed in the op byte
+const errorFlag = 0x80
+
+// String returns the name of an operation
+func (op Op) String() string {
+	switch op {
+	case OpOutput:
+		return "output"
+	case OpInput:
+		return "input"
+	case OpHigh:
+		return "high"
+	case OpLow:
+		return "low"
+	case OpPullUp:
+		return "pullup"
+	case OpPullDown:
+		return "pulldown"
+	case OpRead:
+		return "read"
+	case OpOutputs:
+		return "outputs"
+	case OpReset:
+		return "reset"
+	}
+	return fmt.Sprintf("op(%d)", byte(op))
+}
+
+// Event is one recorded pin operation
+type Event struct {
+	Time   time.Duration // Since the recording started
+	Op     Op
+	Pins   Pins   // Pins operated on
+	Result Pins   // Pins read high by OpRead, outputs found by OpOutputs
+	Err    string // Error returned by the driver, the operation may not have reached the pins
+}
+
+// String formats an event as a line of a dump
+func (e Event) String() string {
+	s := fmt.Sprintf("%12.6f %-8v %v", e.Time.Seconds(), e.Op, e.Pins)
+	if e.Op == OpRead || e.Op == OpOutputs {
+		s += fmt.Sprintf(" -> %v", e.Result)
+	}
+	if e.Err != "" {
+		s += " error: " + e.Err
+	}
+	return s
+}
+
+// Pins is a set of MCP23017 pins, bit 0 is A0 and bit 15 is B7
+type Pins uint16
+
+// PinsOf returns the set of named pins
+func PinsOf(names ...string) (Pins, error) {
+	var pins Pins
+	for _, name := range names {
+		if len(name) != 2 || (name[0] != 'A' && name[0] != 'B') || name[1] < '0' || name[1] > '7' {
+			return 0, fmt.Errorf("record: invalid pin %q", name)
+		}
+		pins |= 1 << ((name[0]-'A')*8 + name[1] - '0')
+	}
+	return pins, nil
+}
+
+// Names returns the names of the pins in the set
+func (p Pins) Names() []string {
+	names := make([]string, 0, bits.OnesCount16(uint16(p)))
+	for bit := 0; bit < 16; bit++ {
+		if p&(1<<bit) != 0 {
+			names = append(names, string(rune('A'+bit/8))+string(rune('0'+bit%8)))
+		}
+	}
+	return names
+}
+
+// String lists the pins, e.g. "B1,B5"
+func (p Pins) String() string {
+	if p == 0 {
+		return "-"
+	}
+	return strings.Join(p.Names(), ",")
+}
+
+// Writer encodes events. Each is an op byte, the time since the previous
+// event in microseconds as a uvarint and the pins as 16 bits little
+// endian, followed by 16 bits of result for reads and a uvarint length and
+// message for errors.
+type Writer struct {
+	w    *bufio.Writer
+	last time.Duration
+}
+
+// NewWriter starts a recording on w
+func NewWriter(w io.Writer) (*Writer, error) {
+	bw := bufio.NewWriter(w)
+	if _, err := bw.Write(magic); err != nil {
+		return nil, err
+	}
+	return &Writer{w: bw}, nil
+}
+
+// Write appends an event, events must come in time order
+func (w *Writer) Write(e Event) error {
+	op := byte(e.Op)
+	if e.Err != "" {
+		op |= errorFlag
+	}
+
+	buf := make([]byte, 0, 16+len(e.Err))
+	buf = append(buf, op)
+	buf = binary.AppendUvarint(buf, uint64((e.Time-w.last)/time.Microsecond))
+	buf = binary.LittleEndian.AppendUint16(buf, uint16(e.Pins))
+	switch {
+	case e.Err != "":
+		buf = binary.AppendUvarint(buf, uint64(len(e.Err)))
+		buf = append(buf, e.Err...)
+	case e.Op == OpRead || e.Op == OpOutputs:
+		buf = binary.LittleEndian.AppendUint16(buf, uint16(e.Result))
+	}
+
+	// Keep times exact to the microsecond however long the recording
+	w.last += (e.Time - w.last).Truncate(time.Microsecond)
+	_, err := w.w.Write(buf)
+	return err
+}
+
+// Flush writes buffered events to the underlying writer
+func (w *Writer) Flush() error {
+	return w.w.Flush()
+}
+
+// Reader decodes a recording
+type Reader struct {
+	r    *bufio.Reader
+	last time.Duration
+}
+
+// ErrFormat is returned for data that is not a recording
+var ErrFormat = errors.New("record: not a recording")
+
+// NewReader starts reading a recording from r
+func NewReader(r io.Reader) (*Reader, error) {
+	br := bufio.NewReader(r)
+	header := make([]byte, len(magic))
+	if _, err := io.ReadFull(br, header); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, ErrFormat
+		}
+		return nil, err
+	}
+	if string(header) != string(magic) {
+		return nil, ErrFormat
+	}
+	return &Reader{r: br}, nil
+}
+
+// Next returns the next event, io.EOF at the end of the recording. A
+// recording cut short, by a crash for instance, ends with
+// io.ErrUnexpectedEOF.
+func (r *Reader) Next() (Event, error) {
+	var e Event
+	op, err := r.r.ReadByte()
+	if err != nil {
+		return e, err
+	}
+	e.Op = Op(op &^ errorFlag)
+	if e.Op == 0 || e.Op > opMax {
+		return e, fmt.Errorf("%w: unknown op %d", ErrFormat, e.Op)
+	}
+
+	delta, err := binary.ReadUvarint(r.r)
+	if err != nil {
+		return e, unexpected(err)
+	}
+	r.last += time.Duration(delta) * time.Microsecond
+	e.Time = r.last
+
+	var pins [2]byte
+	if _, err := io.ReadFull(r.r, pins[:]); err != nil {
+		return e, unexpected(err)
+	}
+	e.Pins = Pins(binary.LittleEndian.Uint16(pins[:]))
+
+	switch {
+	case op&errorFlag != 0:
+		size, err := binary.ReadUvarint(r.r)
+		if err != nil {
+			return e, unexpected(err)
+		}
+		if size > 4096 {
+			return e, fmt.Errorf("%w: error message of %d bytes", ErrFormat, size)
+		}
+		message := make([]byte, size)
+		if _, err := io.ReadFull(r.r, message); err != nil {
+			return e, unexpected(err)
+		}
+		e.Err = string(message)
+	case e.Op == OpRead || e.Op == OpOutputs:
+		var result [2]byte
+		if _, err := io.ReadFull(r.r, result[:]); err != nil {
+			return e, unexpected(err)
+		}
+		e.Result = Pins(binary.LittleEndian.Uint16(result[:]))
+	}
+	return e, nil
+}
+
+// unexpected turns the end of the data inside an event into an error
+func unexpected(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
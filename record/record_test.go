@@ -0,0 +1,97 @@
+package record_test
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/jyap808/charLCDRGBI2C"
+	"github.com/jyap808/charLCDRGBI2C/record"
+	"github.com/jyap808/charLCDRGBI2C/sim"
+)
+
+var update = flag.Bool("update", false, "rewrite the recordings and golden files in testdata")
+
+var heart = []byte{0x00, 0x0a, 0x1f, 0x1f, 0x0e, 0x04, 0x00, 0x00}
+
+// recordSession records an LCD showing a custom character, two lines of
+// text and a green LED
+func recordSession(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	rec, err := record.NewRecorder(sim.New(charLCDRGBI2C.Geometry16x2, sim.Wiring{}), &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lcd, err := charLCDRGBI2C.NewWithDriver(rec, charLCDRGBI2C.Geometry16x2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lcd.CreateChar(0, heart)
+	lcd.SetColor(0, 100, 0)
+	lcd.Message("Hello \x00\nWorld")
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// replay replays a recording into a simulated board
+func replay(t *testing.T, recording []byte) (*sim.Device, error) {
+	t.Helper()
+	r, err := record.NewReader(bytes.NewReader(recording))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dev := sim.New(charLCDRGBI2C.Geometry16x2, sim.Wiring{})
+	_, err = record.Replay(r, dev, -1)
+	return dev, err
+}
+
+func TestGolden(t *testing.T) {
+	recording := recordSession(t)
+	if *update {
+		if err := os.WriteFile("testdata/message.rec", recording, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		dev, err := replay(t, recording)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile("testdata/message.golden", []byte(dev.String()), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile("testdata/message.golden")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A fresh recording, and one kept from an earlier version
+	saved, err := os.ReadFile("testdata/message.rec")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, recording := range map[string][]byte{"fresh": recording, "message.rec": saved} {
+		dev, err := replay(t, recording)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if got := dev.String(); got != string(want) {
+			t.Errorf("%s replays to\n%s\nwant\n%s", name, got, want)
+		}
+		if got := dev.Glyph(0, 0); !bytes.Equal(got, heart) {
+			t.Errorf("%s replays glyph 0 as %v, want %v", name, got, heart)
+		}
+	}
+}
+
+func TestReplayTruncated(t *testing.T) {
+	recording := recordSession(t)
+	if _, err := replay(t, recording[:len(recording)-1]); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Replay of a truncated recording returned %v, want %v", err, io.ErrUnexpectedEOF)
+	}
+}
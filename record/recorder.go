@@ -0,0 +1,150 @@
+package record
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/jyap808/charLCDRGBI2C"
+)
+
+// Recorder is a PinDriver that records every operation before passing it
+// on to another driver
+type Recorder struct {
+	driver charLCDRGBI2C.PinDriver
+
+	mu    sync.Mutex
+	w     *Writer
+	c     io.Closer // Closed by Close when the output is one
+	start time.Time
+	err   error // First error writing the recording
+}
+
+var (
+	_ charLCDRGBI2C.PinDriver       = (*Recorder)(nil)
+	_ charLCDRGBI2C.DirectionReader = (*Recorder)(nil)
+	_ charLCDRGBI2C.Resetter        = (*Recorder)(nil)
+)
+
+// NewRecorder records the operations on driver to w. Close flushes the
+// recording, and closes w when it is an io.Closer.
+func NewRecorder(driver charLCDRGBI2C.PinDriver, w io.Writer) (*Recorder, error) {
+	writer, err := NewWriter(w)
+	if err != nil {
+		return nil, err
+	}
+	r := &Recorder{driver: driver, w: writer, start: time.Now()}
+	if c, ok := w.(io.Closer); ok {
+		r.c = c
+	}
+	return r, nil
+}
+
+func (r *Recorder) Output(pins ...string) error { return r.set(OpOutput, r.driver.Output, pins) }
+func (r *Recorder) Input(pins ...string) error  { return r.set(OpInput, r.driver.Input, pins) }
+func (r *Recorder) High(pins ...string) error   { return r.set(OpHigh, r.driver.High, pins) }
+func (r *Recorder) Low(pins ...string) error    { return r.set(OpLow, r.driver.Low, pins) }
+func (r *Recorder) PullUp(pins ...string) error { return r.set(OpPullUp, r.driver.PullUp, pins) }
+func (r *Recorder) PullDown(pins ...string) error {
+	return r.set(OpPullDown, r.driver.PullDown, pins)
+}
+
+// Read reads pin levels, recording which pins were high
+func (r *Recorder) Read(pins ...string) (map[string]uint8, error) {
+	levels, err := r.driver.Read(pins...)
+
+	var high []string
+	for pin, level := range levels {
+		if level != 0 {
+			high = append(high, pin)
+		}
+	}
+	r.record(OpRead, pins, high, err)
+	return levels, err
+}
+
+// Outputs reads pin directions when the driver is a DirectionReader, and
+// returns errors.ErrUnsupported otherwise
+func (r *Recorder) Outputs(pins ...string) (map[string]bool, error) {
+	reader, ok := r.driver.(charLCDRGBI2C.DirectionReader)
+	if !ok {
+		return nil, errors.ErrUnsupported
+	}
+	outputs, err := reader.Outputs(pins...)
+
+	var out []string
+	for pin, output := range outputs {
+		if output {
+			out = append(out, pin)
+		}
+	}
+	r.record(OpOutputs, pins, out, err)
+	return outputs, err
+}
+
+// Reset resets the expander when the driver is a Resetter, and does
+// nothing otherwise
+func (r *Recorder) Reset() error {
+	resetter, ok := r.driver.(charLCDRGBI2C.Resetter)
+	if !ok {
+		return nil
+	}
+	err := resetter.Reset()
+	r.record(OpReset, nil, nil, err)
+	return err
+}
+
+// Flush writes the buffered part of the recording out
+func (r *Recorder) Flush() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err == nil {
+		r.err = r.w.Flush()
+	}
+	return r.err
+}
+
+// Err returns the first error writing the recording
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// Close flushes the recording and closes its output
+func (r *Recorder) Close() error {
+	err := r.Flush()
+	if r.c != nil {
+		if cerr := r.c.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// set runs and records an operation that sets pins
+func (r *Recorder) set(op Op, fn func(...string) error, pins []string) error {
+	err := fn(pins...)
+	r.record(op, pins, nil, err)
+	return err
+}
+
+// record writes an event, keeping the first write error
+func (r *Recorder) record(op Op, pins, result []string, err error) {
+	e := Event{Op: op}
+	// Invalid pin names are left out, the driver reports them
+	e.Pins, _ = PinsOf(pins...)
+	e.Result, _ = PinsOf(result...)
+	if err != nil {
+		e.Err = err.Error()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return
+	}
+	e.Time = time.Since(r.start)
+	r.err = r.w.Write(e)
+}
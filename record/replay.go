@@ -0,0 +1,69 @@
+package record
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jyap808/charLCDRGBI2C"
+)
+
+// Apply performs a recorded operation on driver, usually a sim.Device.
+// Reads and failed operations change nothing and are skipped.
+func Apply(driver charLCDRGBI2C.PinDriver, e Event) error {
+	if e.Err != "" {
+		return nil
+	}
+
+	pins := e.Pins.Names()
+	switch e.Op {
+	case OpOutput:
+		return driver.Output(pins...)
+	case OpInput:
+		return driver.Input(pins...)
+	case OpHigh:
+		return driver.High(pins...)
+	case OpLow:
+		return driver.Low(pins...)
+	case OpPullUp:
+		return driver.PullUp(pins...)
+	case OpPullDown:
+		return driver.PullDown(pins...)
+	case OpReset:
+		// Setting the MCP23017 up makes every pin an input without pull-up
+		all := Pins(0xFFFF).Names()
+		if err := driver.Input(all...); err != nil {
+			return err
+		}
+		return driver.PullDown(all...)
+	}
+	return nil
+}
+
+// Replay applies the operations of a recording up to the time until to
+// driver, all of them when until is negative. It returns the last event
+// applied. A recording cut short is replayed up to where it ends, and the
+// error then wraps io.ErrUnexpectedEOF.
+func Replay(r *Reader, driver charLCDRGBI2C.PinDriver, until time.Duration) (Event, error) {
+	var last Event
+	for {
+		e, err := r.Next()
+		if err == io.EOF {
+			return last, nil
+		}
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return last, fmt.Errorf("record: recording cut short after %v: %w", last.Time, err)
+		}
+		if err != nil {
+			return last, err
+		}
+		if until >= 0 && e.Time > until {
+			return last, nil
+		}
+		if err := Apply(driver, e); err != nil {
+			return last, err
+		}
+		last = e
+	}
+}
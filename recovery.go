@@ -0,0 +1,195 @@
+package charLCDRGBI2C
+
+import (
+	"time"
+)
+
+// Health reports how the I2C bus to the LCD is doing
+type Health struct {
+	OK            bool      // The last operation reached the LCD, or recovery succeeded
+	Errors        int       // Bus errors seen
+	Recoveries    int       // Successful recoveries
+	LastError     error     // Last bus error, nil when there was none
+	LastErrorTime time.Time // When the last bus error was seen
+}
+
+// recovery holds the bus error handling settings and status
+type recovery struct {
+	retries     int           // Recovery attempts after a bus error
+	backoff     time.Duration // Wait before the first attempt, doubled for every next one
+	maxBackoff  time.Duration // Longest wait between attempts
+	onReconnect func(Health)  // Called after a successful recovery
+
+	used       bool  // The running operation used the bus
+	err        error // First bus error of the running operation
+	recovering bool  // An operation is recovering, the others leave it to it
+	health     Health
+}
+
+// Default recovery settings
+const (
+	defaultRetries    = 5
+	defaultBackoff    = 10 * time.Millisecond
+	defaultMaxBackoff = time.Second
+)
+
+// WithRecovery sets how bus errors are recovered from: up to retries
+// attempts, waiting backoff before the first and doubling the wait for
+// every next one, up to one second. Zero retries turns recovery off. The
+// default is 5 retries from 10ms.
+func WithRecovery(retries int, backoff time.Duration) Option {
+	return func(lcd *CharLCDRGBI2C) {
+		lcd.recovery.retries = retries
+		lcd.recovery.backoff = backoff
+	}
+}
+
+// WithReconnectHandler calls fn after the LCD has been recovered from a bus
+// error. fn runs on the goroutine of the operation that hit the error and
+// may use the LCD.
+func WithReconnectHandler(fn func(Health)) Option {
+	return func(lcd *CharLCDRGBI2C) {
+		lcd.recovery.onReconnect = fn
+	}
+}
+
+// Health returns the bus health of the LCD
+func (lcd *CharLCDRGBI2C) Health() Health {
+	lcd.mu.Lock()
+	defer lcd.mu.Unlock()
+	return lcd.recovery.health
+}
+
+// lock starts an operation on the LCD
+func (lcd *CharLCDRGBI2C) lock() {
+	lcd.mu.Lock()
+}
+
+// unlock ends an operation on the LCD, recovering from a bus error it ran
+// into before returning
+func (lcd *CharLCDRGBI2C) unlock() {
+	if lcd.dirty {
+		lcd.dirty = false
+		lcd.scheduleSave()
+	}
+	recovered := lcd.recoverBus()
+	health := lcd.recovery.health
+	lcd.mu.Unlock()
+
+	if recovered && lcd.recovery.onReconnect != nil {
+		lcd.recovery.onReconnect(health)
+	}
+}
+
+// track records the result of a bus write, returning err unchanged
+func (lcd *CharLCDRGBI2C) track(err error) error {
+	lcd.dirty = true
+	return lcd.trackRead(err)
+}
+
+// trackRead records the result of a bus read, which leaves the state as it
+// was, returning err unchanged
+func (lcd *CharLCDRGBI2C) trackRead(err error) error {
+	lcd.recovery.used = true
+	if err != nil && lcd.recovery.err == nil {
+		lcd.recovery.err = err
+	}
+	return err
+}
+
+// recoverBus brings the LCD back after a bus error: the pins are set up again,
+// the controllers resynced to the bus, the custom characters uploaded and
+// the display redrawn from the framebuffer. It reports whether a recovery
+// succeeded.
+//
+// lcd.mu must be held. It is released while waiting between attempts, so
+// that other goroutines, such as the button watcher, are not held up by an
+// unplugged board. Their operations fail fast meanwhile, and what they
+// drew is redrawn by the next attempt.
+func (lcd *CharLCDRGBI2C) recoverBus() bool {
+	r := &lcd.recovery
+	err := r.err
+	if !r.used {
+		return false
+	}
+	r.used, r.err = false, nil
+	if err == nil {
+		r.health.OK = true
+		return false
+	}
+	r.failed(err)
+	if r.recovering {
+		return false
+	}
+	r.recovering = true
+	defer func() { r.recovering = false }()
+
+	backoff := r.backoff
+	for attempt := 0; attempt < r.retries; attempt++ {
+		lcd.mu.Unlock()
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-lcd.done:
+			timer.Stop()
+		}
+		lcd.mu.Lock()
+		select {
+		case <-lcd.done:
+			// Closed while waiting, Close left the LCD as asked
+			return false
+		default:
+		}
+		backoff = min(2*backoff, r.maxBackoff)
+
+		lcd.reinitialize()
+		err := r.err
+		r.used, r.err = false, nil
+		if err == nil {
+			r.recovered()
+			return true
+		}
+		r.failed(err)
+	}
+
+	// Give up until the next operation runs into the error again
+	return false
+}
+
+// failed records a bus error in the health status
+func (r *recovery) failed(err error) {
+	r.health.OK = false
+	r.health.Errors++
+	r.health.LastError = err
+	r.health.LastErrorTime = time.Now()
+}
+
+// recovered records a successful recovery in the health status
+func (r *recovery) recovered() {
+	r.health.OK = true
+	r.health.Recoveries++
+}
+
+// reinitialize sets the hardware up again from the driver state
+func (lcd *CharLCDRGBI2C) reinitialize() {
+	row, column := lcd.row, lcd.column
+	cursorRow, cursorColumn := lcd.fb.row, lcd.fb.column
+
+	lcd.setupPins()
+	lcd.resync()
+	for location, pattern := range lcd.glyphs {
+		if pattern != nil {
+			lcd.createChar(byte(location), pattern)
+		}
+	}
+	for row := range lcd.fb.cells {
+		lcd.drawLine(row)
+	}
+	if cursorRow >= 0 {
+		lcd.cursorPosition(cursorColumn, cursorRow)
+	}
+	lcd.row, lcd.column = row, column
+
+	lcd.setColor(lcd.colorValue[0], lcd.colorValue[1], lcd.colorValue[2])
+	lcd.setBacklight(lcd.backlight)
+}
@@ -0,0 +1,101 @@
+package charLCDRGBI2C_test
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jyap808/charLCDRGBI2C"
+	"github.com/jyap808/charLCDRGBI2C/sim"
+)
+
+// TestRecoveryRedraws checks that an operation that runs into an unplugged
+// board sets it up again once it is plugged back in, with everything drawn
+// before and by the operation itself
+func TestRecoveryRedraws(t *testing.T) {
+	var reconnects atomic.Int32
+	lcd, dev := sim.NewLCD(t, charLCDRGBI2C.Geometry16x2, sim.Wiring{},
+		charLCDRGBI2C.WithRecovery(20, 5*time.Millisecond),
+		charLCDRGBI2C.WithReconnectHandler(func(charLCDRGBI2C.Health) { reconnects.Add(1) }))
+	arrow := []byte{0x04, 0x02, 0x1f, 0x02, 0x04, 0, 0, 0}
+	lcd.CreateChar(1, arrow)
+	lcd.SetColor(0, 0, 100)
+	lcd.Message("Hello\n\x01")
+	lcd.CursorPosition(1, 1)
+
+	dev.Unplug()
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		dev.Plug() // Powers up from its reset state
+	}()
+	lcd.Message("World")
+
+	want := []string{"Hello           ", "\x01World          "}
+	for row, line := range dev.Codes() {
+		if string(line) != want[row] {
+			t.Errorf("line %d is %q, want %q", row, line, want[row])
+		}
+	}
+	if got := dev.Glyph(1, 1); string(got) != string(arrow) {
+		t.Errorf("character 1 is %#x, want %#x", got, arrow)
+	}
+	if red, green, blue := dev.LED(); red || green || !blue || !dev.Backlight() {
+		t.Errorf("LED red %v green %v blue %v, backlight %v, want blue with the backlight on", red, green, blue, dev.Backlight())
+	}
+
+	health := lcd.Health()
+	if !health.OK || health.Recoveries != 1 || health.Errors == 0 || !errors.Is(health.LastError, sim.ErrUnplugged) {
+		t.Errorf("health %+v, want one recovery from %v", health, sim.ErrUnplugged)
+	}
+	if n := reconnects.Load(); n != 1 {
+		t.Errorf("reconnect handler called %d times, want 1", n)
+	}
+}
+
+func TestRecoveryGivesUp(t *testing.T) {
+	lcd, dev := sim.NewLCD(t, charLCDRGBI2C.Geometry16x2, sim.Wiring{},
+		charLCDRGBI2C.WithRecovery(2, time.Millisecond))
+	dev.Unplug()
+	lcd.SetColor(100, 0, 0)
+
+	// The error and both attempts
+	if health := lcd.Health(); health.OK || health.Errors != 3 || health.Recoveries != 0 {
+		t.Errorf("health %+v, want 3 errors and no recoveries", health)
+	}
+}
+
+// TestRecoveryLetsOthersRun checks that the LCD can be used from other
+// goroutines while an operation waits to retry
+func TestRecoveryLetsOthersRun(t *testing.T) {
+	lcd, dev := sim.NewLCD(t, charLCDRGBI2C.Geometry16x2, sim.Wiring{},
+		charLCDRGBI2C.WithRecovery(3, 200*time.Millisecond))
+	dev.Unplug()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		lcd.Message("Hello")
+	}()
+	for lcd.Health().OK {
+		time.Sleep(time.Millisecond)
+	}
+
+	start := time.Now()
+	lcd.IsButtonPressed(charLCDRGBI2C.SelectButton)
+	lcd.SetColor(0, 100, 0)
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("operations took %v during a recovery, want them to fail fast", elapsed)
+	}
+
+	dev.Plug()
+	<-done
+	if line := string(dev.Codes()[0]); line != "Hello           " {
+		t.Errorf("line 0 is %q after recovering", line)
+	}
+	if red, green, blue := dev.LED(); red || !green || blue {
+		t.Errorf("LED red %v green %v blue %v, want the green set during the recovery", red, green, blue)
+	}
+	if health := lcd.Health(); health.Recoveries != 1 {
+		t.Errorf("health %+v, want one recovery", health)
+	}
+}
@@ -0,0 +1,163 @@
+package render
+
+import (
+	"strconv"
+	"strings"
+)
+
+// romFont lists the characters of the HD44780U A00 character ROM (Japanese
+// standard font), one per line: the code in hex, then seven rows of five
+// dots from the top. The ASCII range has a yen sign at 0x5C and arrows at
+// 0x7E and 0x7F. Of the upper half only the symbols in common use are here,
+// the katakana are not.
+const romFont = `
+20 ..... ..... ..... ..... ..... ..... .....
+21 ..#.. ..#.. ..#.. ..#.. ..... ..... ..#..
+22 .#.#. .#.#. .#.#. ..... ..... ..... .....
+23 .#.#. .#.#. ##### .#.#. ##### .#.#. .#.#.
+24 ..#.. .#### #.#.. .###. ..#.# ####. ..#..
+25 ##... ##..# ...#. ..#.. .#... #..## ...##
+26 .##.. #..#. #.#.. .#... #.#.# #..#. .##.#
+27 .##.. ..#.. .#... ..... ..... ..... .....
+28 ...#. ..#.. .#... .#... .#... ..#.. ...#.
+29 .#... ..#.. ...#. ...#. ...#. ..#.. .#...
+2A ..... ..#.. #.#.# .###. #.#.# ..#.. .....
+2B ..... ..#.. ..#.. ##### ..#.. ..#.. .....
+2C ..... ..... ..... ..... .##.. ..#.. .#...
+2D ..... ..... ..... ##### ..... ..... .....
+2E ..... ..... ..... ..... ..... .##.. .##..
+2F ..... ....# ...#. ..#.. .#... #.... .....
+30 .###. #...# #..## #.#.# ##..# #...# .###.
+31 ..#.. .##.. ..#.. ..#.. ..#.. ..#.. .###.
+32 .###. #...# ....# ...#. ..#.. .#... #####
+33 ##### ...#. ..#.. ...#. ....# #...# .###.
+34 ...#. ..##. .#.#. #..#. ##### ...#. ...#.
+35 ##### #.... ####. ....# ....# #...# .###.
+36 ..##. .#... #.... ####. #...# #...# .###.
+37 ##### ....# ...#. ..#.. .#... .#... .#...
+38 .###. #...# #...# .###. #...# #...# .###.
+39 .###. #...# #...# .#### ....# ...#. .##..
+3A ..... .##.. .##.. ..... .##.. .##.. .....
+3B ..... .##.. .##.. ..... .##.. ..#.. .#...
+3C ...#. ..#.. .#... #.... .#... ..#.. ...#.
+3D ..... ..... ##### ..... ##### ..... .....
+3E .#... ..#.. ...#. ....# ...#. ..#.. .#...
+3F .###. #...# ....# ...#. ..#.. ..... ..#..
+40 .###. #...# ....# .##.# #.#.# #.#.# .###.
+41 .###. #...# #...# #...# ##### #...# #...#
+42 ####. #...# #...# ####. #...# #...# ####.
+43 .###. #...# #.... #.... #.... #...# .###.
+44 ###.. #..#. #...# #...# #...# #..#. ###..
+45 ##### #.... #.... ####. #.... #.... #####
+46 ##### #.... #.... ####. #.... #.... #....
+47 .###. #...# #.... #.### #...# #...# .####
+48 #...# #...# #...# ##### #...# #...# #...#
+49 .###. ..#.. ..#.. ..#.. ..#.. ..#.. .###.
+4A ..### ...#. ...#. ...#. ...#. #..#. .##..
+4B #...# #..#. #.#.. ##... #.#.. #..#. #...#
+4C #.... #.... #.... #.... #.... #.... #####
+4D #...# ##.## #.#.# #.#.# #...# #...# #...#
+4E #...# #...# ##..# #.#.# #..## #...# #...#
+4F .###. #...# #...# #...# #...# #...# .###.
+50 ####. #...# #...# ####. #.... #.... #....
+51 .###. #...# #...# #...# #.#.# #..#. .##.#
+52 ####. #...# #...# ####. #.#.. #..#. #...#
+53 .#### #.... #.... .###. ....# ....# ####.
+54 ##### ..#.. ..#.. ..#.. ..#.. ..#.. ..#..
+55 #...# #...# #...# #...# #...# #...# .###.
+56 #...# #...# #...# #...# #...# .#.#. ..#..
+57 #...# #...# #...# #.#.# #.#.# #.#.# .#.#.
+58 #...# #...# .#.#. ..#.. .#.#. #...# #...#
+59 #...# #...# #...# .#.#. ..#.. ..#.. ..#..
+5A ##### ....# ...#. ..#.. .#... #.... #####
+5B .###. .#... .#... .#... .#... .#... .###.
+5C #...# .#.#. ##### ..#.. ##### ..#.. ..#..
+5D .###. ...#. ...#. ...#. ...#. ...#. .###.
+5E ..#.. .#.#. #...# ..... ..... ..... .....
+5F ..... ..... ..... ..... ..... ..... #####
+60 .#... ..#.. ...#. ..... ..... ..... .....
+61 ..... ..... .###. ....# .#### #...# .####
+62 #.... #.... #.##. ##..# #...# #...# ####.
+63 ..... ..... .###. #.... #.... #...# .###.
+64 ....# ....# .##.# #..## #...# #...# .####
+65 ..... ..... .###. #...# ##### #.... .###.
+66 ..##. .#..# .#... ###.. .#... .#... .#...
+67 ..... .#### #...# #...# .#### ....# .###.
+68 #.... #.... #.##. ##..# #...# #...# #...#
+69 ..#.. ..... .##.. ..#.. ..#.. ..#.. .###.
+6A ...#. ..... ..##. ...#. ...#. #..#. .##..
+6B #.... #.... #..#. #.#.. ##... #.#.. #..#.
+6C .##.. ..#.. ..#.. ..#.. ..#.. ..#.. .###.
+6D ..... ..... ##.#. #.#.# #.#.# #...# #...#
+6E ..... ..... #.##. ##..# #...# #...# #...#
+6F ..... ..... .###. #...# #...# #...# .###.
+70 ..... ..... ####. #...# ####. #.... #....
+71 ..... ..... .##.# #..## .#### ....# ....#
+72 ..... ..... #.##. ##..# #.... #.... #....
+73 ..... ..... .###. #.... .###. ....# ####.
+74 .#... .#... ###.. .#... .#... .#..# ..##.
+75 ..... ..... #...# #...# #...# #..## .##.#
+76 ..... ..... #...# #...# #...# .#.#. ..#..
+77 ..... ..... #...# #...# #.#.# #.#.# .#.#.
+78 ..... ..... #...# .#.#. ..#.. .#.#. #...#
+79 ..... ..... #...# #...# .#### ....# .###.
+7A ..... ..... ##### ...#. ..#.. .#... #####
+7B ...#. ..#.. ..#.. .#... ..#.. ..#.. ...#.
+7C ..#.. ..#.. ..#.. ..#.. ..#.. ..#.. ..#..
+7D .#... ..#.. ..#.. ...#. ..#.. ..#.. .#...
+7E ..... ..#.. ...#. ##### ...#. ..#.. .....
+7F ..... ..#.. .#... ##### .#... ..#.. .....
+A0 ..... ..... ..... ..... ..... ..... .....
+A5 ..... ..... ..... .##.. .##.. ..... .....
+B0 ..... ..... ..... ##### ..... ..... .....
+DF ###.. #.#.. ###.. ..... ..... ..... .....
+E0 ..... ..... .#..# #.#.# #..#. #..#. .##.#
+E4 ..... ..... #...# #...# #..## ###.# #....
+F4 ..... .###. #...# #...# #...# .#.#. ##.##
+FD ..... ..#.. ..... ##### ..... ..#.. .....
+FF ##### ##### ##### ##### ##### ##### #####
+`
+
+// font holds the dot rows of the ROM characters, bit 4 is the leftmost dot
+// like in custom character patterns
+var font = parseFont(romFont)
+
+// parseFont reads a font listing like romFont
+func parseFont(listing string) map[byte][]byte {
+	glyphs := make(map[byte][]byte)
+	for _, line := range strings.Split(strings.TrimSpace(listing), "\n") {
+		fields := strings.Fields(line)
+		code, err := strconv.ParseUint(fields[0], 16, 8)
+		if err != nil {
+			panic("render: bad font code " + fields[0])
+		}
+		rows := make([]byte, 8)
+		for i, dots := range fields[1:] {
+			for _, dot := range dots {
+				rows[i] <<= 1
+				if dot == '#' {
+					rows[i] |= 1
+				}
+			}
+		}
+		glyphs[byte(code)] = rows
+	}
+	return glyphs
+}
+
+// unknownGlyph is drawn for ROM characters missing from romFont
+var unknownGlyph = []byte{0x1F, 0x11, 0x11, 0x11, 0x11, 0x11, 0x1F, 0x00}
+
+// blankGlyph is drawn for codes without a character
+var blankGlyph = make([]byte, 8)
+
+// romGlyph returns the dot rows of a ROM character
+func romGlyph(code byte) []byte {
+	if rows, ok := font[code]; ok {
+		return rows
+	}
+	if code < 0x20 || (code >= 0x80 && code < 0xA0) {
+		return blankGlyph
+	}
+	return unknownGlyph
+}
@@ -0,0 +1,239 @@
+// Package render draws what a simulated panel shows, as ASCII art for
+// terminals and logs or as an image using the HD44780 character ROM and
+// the custom characters, tinted by the RGB backlight:
+//
+//	dev := sim.New(charLCDRGBI2C.Geometry16x2, sim.Wiring{})
+//	...
+//	fmt.Print(render.ASCII(dev))
+//	err := render.PNG(f, dev, render.Options{Scale: 4})
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strings"
+)
+
+// Screen is the state of a panel, sim.Device implements it
+type Screen interface {
+	// Codes returns the character codes visible on each row
+	Codes() [][]byte
+	// Glyph returns the pixel rows of a custom character on the controller
+	// driving a row, 8 with the 5x8 font and 11 with the 5x10 font
+	Glyph(row int, code byte) []byte
+	// Cursor returns the position of the cursor when it is shown
+	Cursor() (column, row int, blink, ok bool)
+	// Underline reports whether the cursor shows as an underline
+	Underline() bool
+	// LED reports which colors of the RGB LED are lit
+	LED() (red, green, blue bool)
+	// Backlight reports whether the backlight is lit
+	Backlight() bool
+}
+
+// ASCII draws the panel in a box. Custom characters show as their location
+// digit, codes outside printable ASCII as '?'. The line below the box tells
+// the backlight and LED state.
+func ASCII(s Screen) string {
+	codes := s.Codes()
+	columns := 0
+	if len(codes) > 0 {
+		columns = len(codes[0])
+	}
+
+	var b strings.Builder
+	border := "+" + strings.Repeat("-", columns) + "+\n"
+
+	b.WriteString(border)
+	for _, line := range codes {
+		b.WriteByte('|')
+		for _, code := range line {
+			switch {
+			case code < 8:
+				b.WriteByte('0' + code)
+			case code < 0x20 || code > 0x7E:
+				b.WriteByte('?')
+			default:
+				b.WriteByte(code)
+			}
+		}
+		b.WriteString("|\n")
+	}
+	b.WriteString(border)
+
+	red, green, blue := s.LED()
+	fmt.Fprintf(&b, "backlight %s, LED red %s green %s blue %s\n",
+		onOff(s.Backlight()), onOff(red), onOff(green), onOff(blue))
+	return b.String()
+}
+
+// Options changes how Image draws the panel
+type Options struct {
+	Scale    int  // Pixels per dot, zero means 4
+	BlinkOn  bool // Draw a blinking cursor in its on phase, all dots lit
+	Negative bool // Lit dots on a dark panel instead of dark dots on a lit one
+}
+
+// Layout in dots
+const (
+	margin  = 3 // Around the characters
+	spacing = 1 // Between characters
+)
+
+// Image draws the panel dot by dot. ROM characters come from the A00
+// character ROM, custom characters from the CGRAM of their controller.
+// The backlight is tinted by the lit LED colors, white when none is lit,
+// and the panel is unlit when neither the backlight nor the LED is on.
+func Image(s Screen, opts Options) *image.RGBA {
+	scale := opts.Scale
+	if scale <= 0 {
+		scale = 4
+	}
+
+	codes := s.Codes()
+	columns := 0
+	if len(codes) > 0 {
+		columns = len(codes[0])
+	}
+	// Characters are as tall as the custom characters of the font in use
+	height := 8
+	if len(codes) > 0 {
+		height = len(s.Glyph(0, 0))
+	}
+
+	width := 2*margin + columns*(5+spacing) - spacing
+	depth := 2*margin + len(codes)*(height+spacing) - spacing
+	img := image.NewRGBA(image.Rect(0, 0, width*scale, depth*scale))
+	colors := palette(s, opts.Negative)
+
+	// Panel background
+	for y := 0; y < img.Rect.Dy(); y++ {
+		for x := 0; x < img.Rect.Dx(); x++ {
+			img.SetRGBA(x, y, colors.background)
+		}
+	}
+
+	cursorColumn, cursorRow, blink, cursor := s.Cursor()
+	underline := cursor && s.Underline()
+	for row, line := range codes {
+		for column, code := range line {
+			var rows []byte
+			if code < 0x10 {
+				rows = s.Glyph(row, code)
+			} else {
+				rows = romGlyph(code)
+			}
+
+			here := cursor && row == cursorRow && column == cursorColumn
+			for dotRow := 0; dotRow < height; dotRow++ {
+				var bits byte
+				if dotRow < len(rows) {
+					bits = rows[dotRow]
+				}
+				switch {
+				case here && blink && opts.BlinkOn:
+					bits = 0x1F
+				case here && underline && dotRow == height-1:
+					bits = 0x1F
+				}
+
+				for dot := 0; dot < 5; dot++ {
+					c := colors.off
+					if bits&(0x10>>dot) != 0 {
+						c = colors.on
+					}
+					x := margin + column*(5+spacing) + dot
+					y := margin + row*(height+spacing) + dotRow
+					drawDot(img, x, y, scale, c)
+				}
+			}
+		}
+	}
+	return img
+}
+
+// PNG writes the panel drawn by Image as a PNG image
+func PNG(w io.Writer, s Screen, opts Options) error {
+	return png.Encode(w, Image(s, opts))
+}
+
+// panelColors are the colors of a panel
+type panelColors struct {
+	background color.RGBA
+	on, off    color.RGBA // Lit and unlit dots
+}
+
+// palette picks the colors of the panel from its backlight and LED
+func palette(s Screen, negative bool) panelColors {
+	red, green, blue := s.LED()
+	lit := s.Backlight() || red || green || blue
+
+	tint := color.RGBA{0xFF, 0xFF, 0xFF, 0xFF}
+	if red || green || blue {
+		tint = color.RGBA{level(red), level(green), level(blue), 0xFF}
+	}
+
+	switch {
+	case !lit && negative:
+		return panelColors{
+			background: color.RGBA{0x10, 0x10, 0x14, 0xFF},
+			on:         color.RGBA{0x30, 0x30, 0x38, 0xFF},
+			off:        color.RGBA{0x16, 0x16, 0x1C, 0xFF},
+		}
+	case !lit:
+		return panelColors{
+			background: color.RGBA{0x5A, 0x60, 0x58, 0xFF},
+			on:         color.RGBA{0x20, 0x24, 0x20, 0xFF},
+			off:        color.RGBA{0x54, 0x5A, 0x52, 0xFF},
+		}
+	case negative:
+		return panelColors{
+			background: shade(tint, 0.08),
+			on:         shade(tint, 1),
+			off:        shade(tint, 0.14),
+		}
+	}
+	return panelColors{
+		background: shade(tint, 0.85),
+		on:         shade(tint, 0.12),
+		off:        shade(tint, 0.78),
+	}
+}
+
+// level returns the color component of a lit or unlit LED
+func level(on bool) uint8 {
+	if on {
+		return 0xFF
+	}
+	return 0x30
+}
+
+// shade scales the brightness of a color
+func shade(c color.RGBA, f float64) color.RGBA {
+	return color.RGBA{uint8(float64(c.R) * f), uint8(float64(c.G) * f), uint8(float64(c.B) * f), 0xFF}
+}
+
+// drawDot fills a dot, leaving a pixel of gap to the next dot when dots
+// are big enough
+func drawDot(img *image.RGBA, x, y, scale int, c color.RGBA) {
+	size := scale
+	if scale >= 3 {
+		size--
+	}
+	for dy := 0; dy < size; dy++ {
+		for dx := 0; dx < size; dx++ {
+			img.SetRGBA(x*scale+dx, y*scale+dy, c)
+		}
+	}
+}
+
+// onOff formats a state
+func onOff(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
+}
@@ -0,0 +1,217 @@
+package render_test
+
+import (
+	"bytes"
+	"flag"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jyap808/charLCDRGBI2C"
+	"github.com/jyap808/charLCDRGBI2C/render"
+	"github.com/jyap808/charLCDRGBI2C/sim"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+var heart = []byte{0x00, 0x0a, 0x1f, 0x1f, 0x0e, 0x04, 0x00, 0x00}
+
+// golden compares got with the golden file testdata/name, rewriting it
+// with -update
+func golden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s differs, got\n%s", name, got)
+	}
+}
+
+// dots draws an image of a lit panel as text, a character per pixel: '#'
+// for lit dots, '.' for unlit ones and spaces for the panel around them
+func dots(img *image.RGBA) []byte {
+	background := img.RGBAAt(0, 0)
+	brightness := func(x, y int) int {
+		c := img.RGBAAt(x, y)
+		return int(c.R) + int(c.G) + int(c.B)
+	}
+	var b strings.Builder
+	for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+		var line strings.Builder
+		for x := img.Rect.Min.X; x < img.Rect.Max.X; x++ {
+			switch {
+			case img.RGBAAt(x, y) == background:
+				line.WriteByte(' ')
+			case brightness(x, y) < brightness(0, 0)/2:
+				line.WriteByte('#')
+			default:
+				line.WriteByte('.')
+			}
+		}
+		b.WriteString(strings.TrimRight(line.String(), " "))
+		b.WriteByte('\n')
+	}
+	return []byte(b.String())
+}
+
+// panel returns a simulated panel showing text, with a heart as custom
+// character 0
+func panel(t *testing.T, geometry charLCDRGBI2C.Geometry, text string, opts ...charLCDRGBI2C.Option) (*charLCDRGBI2C.CharLCDRGBI2C, *sim.Device) {
+	t.Helper()
+	lcd, dev := sim.NewLCD(t, geometry, sim.Wiring{}, opts...)
+	lcd.CreateChar(0, heart)
+	lcd.Message(text)
+	return lcd, dev
+}
+
+func TestASCII(t *testing.T) {
+	lcd, dev := panel(t, charLCDRGBI2C.Geometry16x2, "Hello \x00\nWorld ~")
+	lcd.SetColor(0, 100, 0)
+	golden(t, "hello.txt", []byte(render.ASCII(dev)))
+
+	// Codes outside printable ASCII, backlight off
+	lcd, dev = panel(t, charLCDRGBI2C.Geometry20x4, "Café ß\n\n\nÿ\u007f")
+	lcd.SetBacklight(false)
+	golden(t, "unprintable.txt", []byte(render.ASCII(dev)))
+}
+
+// TestFont draws every ROM character, with blanks for the codes without
+// one and a box for the katakana the font leaves out
+func TestFont(t *testing.T) {
+	var codes []rune
+	for code := rune(0x20); code < 0x80; code++ {
+		codes = append(codes, code)
+	}
+	codes = append(codes, 0xA0, 0xA5, 0xB0, 0xDF, 0xE0, 0xE4, 0xF4, 0xFD, 0xFF, 0x10, 0x80, 0xB1)
+
+	var got []byte
+	for len(codes) > 0 {
+		n := min(32, len(codes))
+		text := string(codes[:min(16, n)])
+		if n > 16 {
+			text += "\n" + string(codes[16:n])
+		}
+		codes = codes[n:]
+
+		_, dev := panel(t, charLCDRGBI2C.Geometry16x2, text)
+		got = append(got, dots(render.Image(dev, render.Options{Scale: 1}))...)
+	}
+	golden(t, "font.txt", got)
+}
+
+// TestLayout draws the cursor, the 5x10 font and the gaps between big dots
+func TestLayout(t *testing.T) {
+	tests := []struct {
+		name string
+		draw func(t *testing.T) *sim.Device
+		opts render.Options
+	}{
+		{"underline.txt", func(t *testing.T) *sim.Device {
+			lcd, dev := panel(t, charLCDRGBI2C.Geometry8x2, "Hi")
+			lcd.CursorPosition(1, 1)
+			lcd.SetCursor(true)
+			return dev
+		}, render.Options{Scale: 1}},
+		{"blink.txt", func(t *testing.T) *sim.Device {
+			lcd, dev := panel(t, charLCDRGBI2C.Geometry8x2, "Hi")
+			lcd.CursorPosition(0, 0)
+			lcd.SetBlink(true)
+			return dev
+		}, render.Options{Scale: 1, BlinkOn: true}},
+		{"blink-off.txt", func(t *testing.T) *sim.Device {
+			lcd, dev := panel(t, charLCDRGBI2C.Geometry8x2, "Hi")
+			lcd.CursorPosition(0, 0)
+			lcd.SetBlink(true)
+			return dev
+		}, render.Options{Scale: 1}},
+		{"font5x10.txt", func(t *testing.T) *sim.Device {
+			lcd, dev := sim.NewLCD(t, charLCDRGBI2C.Geometry16x1, sim.Wiring{}, charLCDRGBI2C.WithFont5x10())
+			lcd.CreateChar(0, []byte{0x1f, 0, 0x1f, 0, 0x1f, 0, 0x1f, 0, 0x1f, 0, 0x1f})
+			lcd.Message("gjpqy\x00")
+			return dev
+		}, render.Options{Scale: 1}},
+		{"scale3.txt", func(t *testing.T) *sim.Device {
+			_, dev := panel(t, charLCDRGBI2C.Geometry8x2, "\x00")
+			return dev
+		}, render.Options{Scale: 3}},
+	}
+	for _, tt := range tests {
+		golden(t, tt.name, dots(render.Image(tt.draw(t), tt.opts)))
+	}
+}
+
+// TestPNG checks the colors of the panel for the backlight and LED states
+func TestPNG(t *testing.T) {
+	tests := []struct {
+		name      string
+		color     [3]int
+		backlight bool
+		negative  bool
+	}{
+		{"white.png", [3]int{0, 0, 0}, true, false},
+		{"green.png", [3]int{0, 100, 0}, true, false},
+		{"magenta-negative.png", [3]int{100, 0, 100}, false, true},
+		{"unlit.png", [3]int{0, 0, 0}, false, false},
+		{"unlit-negative.png", [3]int{0, 0, 0}, false, true},
+	}
+	for _, tt := range tests {
+		lcd, dev := panel(t, charLCDRGBI2C.Geometry8x2, "Hi \x00")
+		lcd.SetColor(tt.color[0], tt.color[1], tt.color[2])
+		lcd.SetBacklight(tt.backlight)
+
+		var buf bytes.Buffer
+		if err := render.PNG(&buf, dev, render.Options{Scale: 2, Negative: tt.negative}); err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join("testdata", tt.name)
+		if *update {
+			if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// Compare the pixels, the encoding may change between Go versions
+		got, err := png.Decode(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := png.Decode(f)
+		f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Bounds().Eq(want.Bounds()) {
+			t.Errorf("%s: image is %v, want %v", tt.name, got.Bounds(), want.Bounds())
+		} else if p, ok := firstDifference(got, want); ok {
+			t.Errorf("%s: pixel %v is %v, want %v", tt.name, p, got.At(p.X, p.Y), want.At(p.X, p.Y))
+		}
+	}
+}
+
+// firstDifference returns the first pixel that differs between two images
+// of the same size
+func firstDifference(a, b image.Image) (image.Point, bool) {
+	for y := a.Bounds().Min.Y; y < a.Bounds().Max.Y; y++ {
+		for x := a.Bounds().Min.X; x < a.Bounds().Max.X; x++ {
+			if a.At(x, y) != b.At(x, y) {
+				return image.Pt(x, y), true
+			}
+		}
+	}
+	return image.Point{}, false
+}
@@ -0,0 +1,242 @@
+// Package schedule switches the backlight and RGB LED between profiles by
+// time of day, e.g. turning the backlight off at night.
+//
+//	s := schedule.New(lcd, schedule.Profile{Backlight: true, Color: [3]int{0, 0, 100}},
+//		schedule.Rule{Start: schedule.At(22, 0), End: schedule.At(7, 0), Profile: schedule.Profile{Color: [3]int{20, 0, 0}}})
+//	go s.Run(ctx)
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Target is what a Scheduler drives, implemented by charLCDRGBI2C.Display
+type Target interface {
+	SetBacklight(on bool) error
+	SetColor(red, green, blue int)
+}
+
+// Clock tells the time, tests can swap in a fake one
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// systemClock is the real time
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// TimeOfDay is a time since midnight
+type TimeOfDay time.Duration
+
+// At returns the time of day of hour:minute
+func At(hour, minute int) TimeOfDay {
+	return TimeOfDay(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+}
+
+// ParseTimeOfDay parses a time of day written as 15:04
+func ParseTimeOfDay(s string) (TimeOfDay, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("schedule: invalid time of day %q", s)
+	}
+	return At(t.Hour(), t.Minute()), nil
+}
+
+// on returns the time of day on the date of t, by the wall clock of its
+// location so that days with a daylight saving change come out right
+func (tod TimeOfDay) on(t time.Time) time.Time {
+	d := time.Duration(tod)
+	return time.Date(t.Year(), t.Month(), t.Day(),
+		int(d/time.Hour), int(d/time.Minute)%60, int(d/time.Second)%60, int(d%time.Second), t.Location())
+}
+
+// String formats the time of day as 15:04
+func (tod TimeOfDay) String() string {
+	d := time.Duration(tod)
+	return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// Profile is the state of the backlight and LED
+type Profile struct {
+	Backlight bool
+	Color     [3]int // RGB LED values (0-100)
+}
+
+// Rule applies a profile from Start until End. Rules where End is before
+// Start run past midnight, rules where they are equal last all day.
+type Rule struct {
+	Start    TimeOfDay
+	End      TimeOfDay
+	Weekdays []time.Weekday // Days the rule starts on, every day when empty
+	Profile  Profile
+}
+
+// matches reports whether the rule is active at t
+func (r Rule) matches(t time.Time) bool {
+	tod := timeOfDay(t)
+	switch {
+	case r.Start < r.End:
+		return r.startsOn(t) && tod >= r.Start && tod < r.End
+	case r.Start > r.End:
+		// Started today, or yesterday and still running after midnight
+		return (r.startsOn(t) && tod >= r.Start) || (r.startsOn(t.AddDate(0, 0, -1)) && tod < r.End)
+	default:
+		return r.startsOn(t)
+	}
+}
+
+// startsOn reports whether the rule starts on the day of t
+func (r Rule) startsOn(t time.Time) bool {
+	return len(r.Weekdays) == 0 || slices.Contains(r.Weekdays, t.Weekday())
+}
+
+// Scheduler applies the profile of the first rule that matches the time,
+// or the default profile when none does
+type Scheduler struct {
+	Clock Clock // Defaults to the system clock
+
+	target   Target
+	def      Profile
+	rules    []Rule
+	mu       sync.Mutex
+	override *Profile      // Manual override until the next transition
+	applied  *Profile      // Profile last applied to the target
+	changed  chan struct{} // Signals Run that the override changed
+}
+
+// New creates a scheduler for target
+func New(target Target, def Profile, rules ...Rule) *Scheduler {
+	return &Scheduler{
+		Clock:   systemClock{},
+		target:  target,
+		def:     def,
+		rules:   rules,
+		changed: make(chan struct{}, 1),
+	}
+}
+
+// Override applies p until the next transition between rules
+func (s *Scheduler) Override(p Profile) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.override = &p
+	select {
+	case s.changed <- struct{}{}:
+	default:
+	}
+}
+
+// Current returns the profile in effect, including any override
+func (s *Scheduler) Current() Profile {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.current(s.Clock.Now())
+}
+
+// current returns the profile in effect at t, s.mu must be held
+func (s *Scheduler) current(t time.Time) Profile {
+	if s.override != nil {
+		return *s.override
+	}
+	if i := s.active(t); i >= 0 {
+		return s.rules[i].Profile
+	}
+	return s.def
+}
+
+// active returns the index of the rule in effect at t, -1 for the default
+func (s *Scheduler) active(t time.Time) int {
+	for i, r := range s.rules {
+		if r.matches(t) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Next returns when the rule in effect changes next after t, zero when it
+// never does
+func (s *Scheduler) Next(t time.Time) time.Time {
+	var boundaries []TimeOfDay
+	for _, r := range s.rules {
+		boundaries = append(boundaries, r.Start, r.End)
+	}
+	slices.Sort(boundaries)
+
+	now := s.active(t)
+	for day := 0; day <= 7; day++ {
+		date := t.AddDate(0, 0, day)
+		for _, tod := range boundaries {
+			next := tod.on(date)
+			if next.After(t) && s.active(next) != now {
+				return next
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// recheck is the longest Run sleeps. Timers run on the monotonic clock, so
+// Run looks at the wall clock this often to catch it being set, e.g. by NTP
+// after boot.
+const recheck = time.Minute
+
+// Run applies profiles as time passes until ctx is done or the target
+// fails
+func (s *Scheduler) Run(ctx context.Context) error {
+	var next time.Time
+	for {
+		now := s.Clock.Now()
+		if !next.IsZero() && !now.Before(next) {
+			// Overrides expire at the next transition
+			s.mu.Lock()
+			s.override = nil
+			s.mu.Unlock()
+		}
+		if err := s.apply(now); err != nil {
+			return fmt.Errorf("schedule: %w", err)
+		}
+
+		wait := recheck
+		if next = s.Next(now); !next.IsZero() {
+			wait = min(wait, next.Sub(now))
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.changed:
+		case <-s.Clock.After(wait):
+		}
+	}
+}
+
+// apply sets the target to the profile in effect at t when it changed
+func (s *Scheduler) apply(t time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := s.current(t)
+	if s.applied != nil && *s.applied == p {
+		return nil
+	}
+	if err := s.target.SetBacklight(p.Backlight); err != nil {
+		return err
+	}
+	s.target.SetColor(p.Color[0], p.Color[1], p.Color[2])
+	s.applied = &p
+	return nil
+}
+
+// timeOfDay returns the time since midnight of t
+func timeOfDay(t time.Time) TimeOfDay {
+	return At(t.Hour(), t.Minute()) + TimeOfDay(time.Duration(t.Second())*time.Second+time.Duration(t.Nanosecond()))
+}
@@ -0,0 +1,212 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+	_ "time/tzdata" // Europe/Berlin on systems without a zone database
+)
+
+// fakeClock is a Clock whose time and timers the test moves
+type fakeClock struct {
+	mu    sync.Mutex
+	now   time.Time
+	waits chan fakeTimer // Timers started by After
+}
+
+// fakeTimer is a timer started by fakeClock.After
+type fakeTimer struct {
+	d  time.Duration
+	ch chan time.Time
+}
+
+func newFakeClock(now time.Time) *fakeClock {
+	return &fakeClock{now: now, waits: make(chan fakeTimer)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	timer := fakeTimer{d: d, ch: make(chan time.Time, 1)}
+	c.waits <- timer
+	return timer.ch
+}
+
+// set moves the wall clock without firing timers, like NTP does
+func (c *fakeClock) set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+// fire moves the clock to the end of timer and fires it
+func (c *fakeClock) fire(timer fakeTimer) {
+	c.mu.Lock()
+	c.now = c.now.Add(timer.d)
+	now := c.now
+	c.mu.Unlock()
+	timer.ch <- now
+}
+
+// fakeTarget records what the scheduler applied
+type fakeTarget struct {
+	mu      sync.Mutex
+	profile Profile
+	err     error // Returned by SetBacklight
+}
+
+func (t *fakeTarget) SetBacklight(on bool) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.err != nil {
+		return t.err
+	}
+	t.profile.Backlight = on
+	return nil
+}
+
+func (t *fakeTarget) SetColor(red, green, blue int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.profile.Color = [3]int{red, green, blue}
+}
+
+func (t *fakeTarget) current() Profile {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.profile
+}
+
+var (
+	day   = Profile{Backlight: true, Color: [3]int{0, 0, 100}}
+	night = Profile{Color: [3]int{20, 0, 0}}
+	rule  = Rule{Start: At(22, 0), End: At(7, 0), Profile: night}
+)
+
+// run starts s and returns its first timer, and a function stopping it
+// that returns the result of Run
+func run(t *testing.T, s *Scheduler, clock *fakeClock) (fakeTimer, func() error) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() {
+		result <- s.Run(ctx)
+	}()
+	timer := <-clock.waits
+	return timer, func() error {
+		cancel()
+		return <-result
+	}
+}
+
+func TestNext(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := New(&fakeTarget{}, day, rule)
+
+	tests := []struct {
+		name string
+		t    time.Time
+		want time.Time
+	}{
+		{"evening", time.Date(2026, 6, 1, 12, 0, 0, 0, berlin), time.Date(2026, 6, 1, 22, 0, 0, 0, berlin)},
+		{"night", time.Date(2026, 6, 1, 23, 0, 0, 0, berlin), time.Date(2026, 6, 2, 7, 0, 0, 0, berlin)},
+		{"clocks go forward", time.Date(2026, 3, 29, 0, 30, 0, 0, berlin), time.Date(2026, 3, 29, 7, 0, 0, 0, berlin)},
+		{"clocks go back", time.Date(2026, 10, 25, 0, 30, 0, 0, berlin), time.Date(2026, 10, 25, 7, 0, 0, 0, berlin)},
+	}
+	for _, tt := range tests {
+		if got := s.Next(tt.t); !got.Equal(tt.want) {
+			t.Errorf("%s: Next(%v) = %v, want %v", tt.name, tt.t, got, tt.want)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	clock := newFakeClock(time.Date(2026, 6, 1, 21, 0, 0, 0, time.UTC))
+	target := &fakeTarget{}
+	s := New(target, day, rule)
+	s.Clock = clock
+
+	timer, stop := run(t, s, clock)
+	if got := target.current(); got != day {
+		t.Errorf("at 21:00 got %+v, want %+v", got, day)
+	}
+	// Sleeps are cut short to notice the wall clock being set
+	for clock.Now().Hour() != 22 {
+		if timer.d > recheck {
+			t.Fatalf("Run sleeps %v, longer than %v", timer.d, recheck)
+		}
+		clock.fire(timer)
+		timer = <-clock.waits
+	}
+	if got := target.current(); got != night {
+		t.Errorf("at 22:00 got %+v, want %+v", got, night)
+	}
+	if err := stop(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Run returned %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestRunClockSet(t *testing.T) {
+	// A board without a real time clock boots in 1970 until NTP sets it
+	clock := newFakeClock(time.Date(1970, 1, 1, 0, 0, 10, 0, time.UTC))
+	target := &fakeTarget{}
+	s := New(target, day, rule)
+	s.Clock = clock
+
+	timer, stop := run(t, s, clock)
+	defer stop()
+	if got := target.current(); got != night {
+		t.Errorf("at boot got %+v, want %+v", got, night)
+	}
+
+	clock.set(time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC))
+	timer.ch <- clock.Now()
+	<-clock.waits
+	if got := target.current(); got != day {
+		t.Errorf("after the clock was set got %+v, want %+v", got, day)
+	}
+}
+
+func TestOverride(t *testing.T) {
+	clock := newFakeClock(time.Date(2026, 6, 1, 21, 59, 0, 0, time.UTC))
+	target := &fakeTarget{}
+	s := New(target, day, rule)
+	s.Clock = clock
+
+	timer, stop := run(t, s, clock)
+	defer stop()
+
+	manual := Profile{Backlight: true, Color: [3]int{0, 100, 0}}
+	s.Override(manual)
+	timer = <-clock.waits
+	if got := target.current(); got != manual {
+		t.Errorf("after Override got %+v, want %+v", got, manual)
+	}
+
+	// The override ends at 22:00
+	clock.fire(timer)
+	<-clock.waits
+	if got := target.current(); got != night {
+		t.Errorf("at 22:00 got %+v, want %+v", got, night)
+	}
+}
+
+func TestRunError(t *testing.T) {
+	clock := newFakeClock(time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC))
+	broken := errors.New("bus error")
+	s := New(&fakeTarget{err: broken}, day, rule)
+	s.Clock = clock
+
+	if err := s.Run(context.Background()); !errors.Is(err, broken) {
+		t.Errorf("Run returned %v, want %v", err, broken)
+	}
+}
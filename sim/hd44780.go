@@ -0,0 +1,190 @@
+package sim
+
+// HD44780 simulates the instruction set of an HD44780 controller, fed one
+// bus transfer at a time
+type HD44780 struct {
+	DDRAM [0x80]byte // Display data, visible through the row offsets
+	CGRAM [0x40]byte // Custom character patterns
+
+	AddressCounter byte // DDRAM or CGRAM address of the next data access
+	CGRAMSelected  bool // AddressCounter points into CGRAM
+	Increment      bool // Address counter moves right after data accesses
+	ShiftOnEntry   bool // Display shifts after data writes
+	Shift          int  // Display shift, positive to the left
+
+	DisplayOn bool
+	CursorOn  bool
+	BlinkOn   bool
+
+	EightBit bool // 8-bit interface, the state at power on
+	TwoLine  bool
+	Font5x10 bool
+
+	nibble   byte // Upper half of a byte in 4-bit mode
+	hasFirst bool // nibble holds the first half
+}
+
+// NewHD44780 returns a controller in its power on state
+func NewHD44780() *HD44780 {
+	c := &HD44780{EightBit: true, Increment: true}
+	for i := range c.DDRAM {
+		c.DDRAM[i] = ' '
+	}
+	return c
+}
+
+// Latch takes the value on D7-D0 when the enable pin falls. In 4-bit mode
+// only D7-D4 are used and a byte takes two transfers.
+func (c *HD44780) Latch(rs bool, bus byte) {
+	if c.EightBit {
+		c.execute(rs, bus)
+		return
+	}
+
+	if !c.hasFirst {
+		c.nibble, c.hasFirst = bus&0xF0, true
+		return
+	}
+	c.hasFirst = false
+	c.execute(rs, c.nibble|bus>>4)
+}
+
+// execute runs an instruction, or writes data when rs is set
+func (c *HD44780) execute(rs bool, value byte) {
+	if rs {
+		c.writeData(value)
+		return
+	}
+
+	switch {
+	case value&0x80 != 0:
+		// Set DDRAM address
+		c.CGRAMSelected = false
+		c.AddressCounter = value & 0x7F
+	case value&0x40 != 0:
+		// Set CGRAM address
+		c.CGRAMSelected = true
+		c.AddressCounter = value & 0x3F
+	case value&0x20 != 0:
+		// Function set
+		c.EightBit = value&0x10 != 0
+		c.TwoLine = value&0x08 != 0
+		c.Font5x10 = value&0x04 != 0
+		c.hasFirst = false
+	case value&0x10 != 0:
+		// Cursor or display shift
+		right := value&0x04 != 0
+		if value&0x08 != 0 {
+			if right {
+				c.Shift--
+			} else {
+				c.Shift++
+			}
+		} else {
+			c.moveAddress(right)
+		}
+	case value&0x08 != 0:
+		// Display control
+		c.DisplayOn = value&0x04 != 0
+		c.CursorOn = value&0x02 != 0
+		c.BlinkOn = value&0x01 != 0
+	case value&0x04 != 0:
+		// Entry mode set
+		c.Increment = value&0x02 != 0
+		c.ShiftOnEntry = value&0x01 != 0
+	case value&0x02 != 0:
+		// Return home
+		c.CGRAMSelected = false
+		c.AddressCounter = 0
+		c.Shift = 0
+	case value&0x01 != 0:
+		// Clear display
+		for i := range c.DDRAM {
+			c.DDRAM[i] = ' '
+		}
+		c.CGRAMSelected = false
+		c.AddressCounter = 0
+		c.Increment = true
+		c.Shift = 0
+	}
+}
+
+// writeData stores a byte at the address counter and advances it
+func (c *HD44780) writeData(value byte) {
+	if c.CGRAMSelected {
+		c.CGRAM[c.AddressCounter&0x3F] = value
+		if c.Increment {
+			c.AddressCounter = (c.AddressCounter + 1) & 0x3F
+		} else {
+			c.AddressCounter = (c.AddressCounter - 1) & 0x3F
+		}
+		return
+	}
+
+	c.DDRAM[c.AddressCounter&0x7F] = value
+	c.moveAddress(c.Increment)
+	if c.ShiftOnEntry {
+		if c.Increment {
+			c.Shift++
+		} else {
+			c.Shift--
+		}
+	}
+}
+
+// moveAddress steps the DDRAM address counter, wrapping between lines
+func (c *HD44780) moveAddress(right bool) {
+	if c.CGRAMSelected {
+		return
+	}
+
+	base, size := c.line(c.AddressCounter)
+	index := int(c.AddressCounter - base)
+	if right {
+		index++
+	} else {
+		index--
+	}
+
+	switch {
+	case !c.TwoLine:
+		index = (index + size) % size
+	case index >= size:
+		// Past the end of a line continues on the other line
+		index = 0
+		base ^= 0x40
+	case index < 0:
+		index = size - 1
+		base ^= 0x40
+	}
+	c.AddressCounter = base + byte(index)
+}
+
+// line returns the start address and size of the DDRAM line holding address
+func (c *HD44780) line(address byte) (base byte, size int) {
+	if !c.TwoLine {
+		return 0x00, 80
+	}
+	return address & 0x40, 40
+}
+
+// Char returns the character shown at a column of the line starting at
+// offset, taking the display shift into account
+func (c *HD44780) Char(offset byte, column int) byte {
+	base, size := c.line(offset)
+	index := int(offset-base) + column + c.Shift
+	index = (index%size + size) % size
+	return c.DDRAM[base+byte(index)]
+}
+
+// Glyph returns the pixel rows of a custom character, 8 with the 5x8 font
+// and 11 with the 5x10 font
+func (c *HD44780) Glyph(code byte) []byte {
+	location := int(code&0x7) * 8
+	rows := 8
+	if c.Font5x10 {
+		// Bit 0 of the code is ignored, codes 2n and 2n+1 share a pattern
+		location, rows = int(code&0x6)*8, 11
+	}
+	return append([]byte(nil), c.CGRAM[location:location+rows]...)
+}
@@ -0,0 +1,341 @@
+// Package sim simulates the RGB1602 board: an MCP23017 with an HD44780
+// LCD, RGB LED, backlight and buttons wired to it. A Device is a
+// charLCDRGBI2C.PinDriver, so the driver runs against it unchanged:
+//
+//	dev := sim.New(charLCDRGBI2C.Geometry16x2, sim.Wiring{})
+//	lcd, err := charLCDRGBI2C.NewWithDriver(dev, charLCDRGBI2C.Geometry16x2)
+//	lcd.Message("Hello")
+//	fmt.Print(dev)
+package sim
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/jyap808/charLCDRGBI2C"
+	"github.com/jyap808/charLCDRGBI2C/render"
+)
+
+// Wiring describes how the LCD is connected to the MCP23017
+type Wiring struct {
+	EnablePins []string // Enable pin of each controller, defaults to LcdEnablePin
+	DataPins   []string // D4-D7, or D0-D7 for an 8-bit bus, defaults to the board's D4-D7
+}
+
+// Device is a simulated board
+type Device struct {
+	mu          sync.Mutex
+	geometry    charLCDRGBI2C.Geometry
+	wiring      Wiring
+	controllers []*HD44780
+	output      map[string]bool // Pin direction is output
+	latch       map[string]bool // Output latch is high
+	pullUp      map[string]bool // Pull-up resistor enabled
+	pressed     map[string]bool // Button held down
+	unplugged   bool            // Transfers fail, see Unplug
+}
+
+// ErrUnplugged is returned for every transfer while the board is unplugged
+var ErrUnplugged = errors.New("sim: board unplugged")
+
+var (
+	_ charLCDRGBI2C.PinDriver       = (*Device)(nil)
+	_ charLCDRGBI2C.DirectionReader = (*Device)(nil)
+	_ render.Screen                 = (*Device)(nil)
+)
+
+// New creates a board with a panel of the given geometry, every pin starts
+// as an input like on a freshly reset MCP23017
+func New(geometry charLCDRGBI2C.Geometry, wiring Wiring) *Device {
+	if len(wiring.EnablePins) == 0 {
+		wiring.EnablePins = []string{charLCDRGBI2C.LcdEnablePin}
+	}
+	if len(wiring.DataPins) == 0 {
+		wiring.DataPins = []string{charLCDRGBI2C.LcdD4Pin, charLCDRGBI2C.LcdD5Pin, charLCDRGBI2C.LcdD6Pin, charLCDRGBI2C.LcdD7Pin}
+	}
+
+	d := &Device{
+		geometry: geometry,
+		wiring:   wiring,
+		output:   make(map[string]bool),
+		latch:    make(map[string]bool),
+		pullUp:   make(map[string]bool),
+		pressed:  make(map[string]bool),
+	}
+	for range wiring.EnablePins {
+		d.controllers = append(d.controllers, NewHD44780())
+	}
+	return d
+}
+
+// PowerCycle resets the MCP23017 and the LCD controllers as if the board
+// lost power for a moment
+func (d *Device) PowerCycle() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.powerOn()
+}
+
+// Unplug disconnects the board, transfers fail with ErrUnplugged until
+// Plug is called
+func (d *Device) Unplug() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.unplugged = true
+}
+
+// Plug connects the board again, it powers up from its reset state
+func (d *Device) Plug() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.unplugged = false
+	d.powerOn()
+}
+
+// powerOn puts the pins and controllers in their power on state
+func (d *Device) powerOn() {
+	clear(d.output)
+	clear(d.latch)
+	clear(d.pullUp)
+	for _, c := range d.controllers {
+		*c = *NewHD44780()
+	}
+}
+
+// Geometry returns the geometry of the panel
+func (d *Device) Geometry() charLCDRGBI2C.Geometry {
+	return d.geometry
+}
+
+func (d *Device) Output(pins ...string) error   { return d.set(d.output, true, pins) }
+func (d *Device) Input(pins ...string) error    { return d.set(d.output, false, pins) }
+func (d *Device) High(pins ...string) error     { return d.set(d.latch, true, pins) }
+func (d *Device) PullUp(pins ...string) error   { return d.set(d.pullUp, true, pins) }
+func (d *Device) PullDown(pins ...string) error { return d.set(d.pullUp, false, pins) }
+
+// Low drives pins low, latching the data bus into a controller when its
+// enable pin falls
+func (d *Device) Low(pins ...string) error {
+	d.mu.Lock()
+	falling := make([]bool, len(d.wiring.EnablePins))
+	for i, pin := range d.wiring.EnablePins {
+		falling[i] = d.latch[pin]
+	}
+	d.mu.Unlock()
+
+	if err := d.set(d.latch, false, pins); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for i, pin := range d.wiring.EnablePins {
+		if falling[i] && !d.latch[pin] {
+			d.controllers[i].Latch(d.latch[charLCDRGBI2C.LcdRsPin], d.bus())
+		}
+	}
+	return nil
+}
+
+// Read returns the level of pins: outputs read back their latch, inputs
+// are low while their button is pressed and high with a pull-up
+func (d *Device) Read(pins ...string) (map[string]uint8, error) {
+	if err := validPins(pins); err != nil {
+		return nil, err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.unplugged {
+		return nil, ErrUnplugged
+	}
+
+	levels := make(map[string]uint8)
+	for _, pin := range pins {
+		var high bool
+		switch {
+		case d.output[pin]:
+			high = d.latch[pin]
+		case d.pressed[pin]:
+			high = false
+		default:
+			high = d.pullUp[pin]
+		}
+		if high {
+			levels[pin] = 1
+		} else {
+			levels[pin] = 0
+		}
+	}
+	return levels, nil
+}
+
+// Outputs reports which pins are outputs
+func (d *Device) Outputs(pins ...string) (map[string]bool, error) {
+	if err := validPins(pins); err != nil {
+		return nil, err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.unplugged {
+		return nil, ErrUnplugged
+	}
+	outputs := make(map[string]bool)
+	for _, pin := range pins {
+		outputs[pin] = d.output[pin]
+	}
+	return outputs, nil
+}
+
+// set updates a pin register
+func (d *Device) set(register map[string]bool, value bool, pins []string) error {
+	if err := validPins(pins); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.unplugged {
+		return ErrUnplugged
+	}
+	for _, pin := range pins {
+		register[pin] = value
+	}
+	return nil
+}
+
+// bus returns the data pins as D7-D0, in 4-bit mode D3-D0 are not wired
+func (d *Device) bus() byte {
+	var value byte
+	for bit, pin := range d.wiring.DataPins {
+		if d.output[pin] && d.latch[pin] {
+			value |= 1 << bit
+		}
+	}
+	if len(d.wiring.DataPins) == 4 {
+		value <<= 4
+	}
+	return value
+}
+
+// Press holds a button down, e.g. charLCDRGBI2C.SelectButton
+func (d *Device) Press(button string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.pressed[button] = true
+}
+
+// Release lets go of a button
+func (d *Device) Release(button string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.pressed, button)
+}
+
+// Backlight reports whether the backlight is lit, it is on while the
+// backlight pin is an output
+func (d *Device) Backlight() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.output[charLCDRGBI2C.BacklightPin]
+}
+
+// LED reports which colors of the common anode RGB LED are lit, each is on
+// while its pin drives low
+func (d *Device) LED() (red, green, blue bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	lit := func(pin string) bool {
+		return d.output[pin] && !d.latch[pin]
+	}
+	return lit(charLCDRGBI2C.RedPin), lit(charLCDRGBI2C.GreenPin), lit(charLCDRGBI2C.BluePin)
+}
+
+// Controller returns the simulated HD44780 driving a row
+func (d *Device) Controller(row int) *HD44780 {
+	return d.controllers[d.controllerOf(row)]
+}
+
+// controllerOf returns the index of the controller driving a row
+func (d *Device) controllerOf(row int) int {
+	return row / (d.geometry.Lines / len(d.controllers))
+}
+
+// Codes returns the character codes visible on each row. Rows of a
+// controller whose display is off are blank.
+func (d *Device) Codes() [][]byte {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	codes := make([][]byte, d.geometry.Lines)
+	for row := range codes {
+		c := d.controllers[d.controllerOf(row)]
+		codes[row] = make([]byte, d.geometry.Columns)
+		for column := range codes[row] {
+			codes[row][column] = ' '
+			if c.DisplayOn {
+				codes[row][column] = c.Char(d.geometry.RowOffsets[row], column)
+			}
+		}
+	}
+	return codes
+}
+
+// Cursor returns the position of the cursor when a controller shows it
+func (d *Device) Cursor() (column, row int, blink, ok bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for row, offset := range d.geometry.RowOffsets {
+		c := d.controllers[d.controllerOf(row)]
+		if !c.DisplayOn || c.CGRAMSelected || !(c.CursorOn || c.BlinkOn) {
+			continue
+		}
+		for column := 0; column < d.geometry.Columns; column++ {
+			base, size := c.line(offset)
+			index := (int(offset-base)+column+c.Shift)%size + size
+			if base+byte(index%size) == c.AddressCounter {
+				return column, row, c.BlinkOn, true
+			}
+		}
+	}
+	return 0, 0, false, false
+}
+
+// Underline reports whether the cursor shows as an underline, besides or
+// instead of blinking
+func (d *Device) Underline() bool {
+	_, row, _, ok := d.Cursor()
+	if !ok {
+		return false
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.controllers[d.controllerOf(row)].CursorOn
+}
+
+// Glyph returns the pixel rows of a custom character on the controller
+// driving a row, 8 with the 5x8 font and 11 with the 5x10 font
+func (d *Device) Glyph(row int, code byte) []byte {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.controllers[d.controllerOf(row)].Glyph(code)
+}
+
+// String draws the panel as ASCII art, see render.ASCII
+func (d *Device) String() string {
+	return render.ASCII(d)
+}
+
+// validPins checks pin names are "A0" to "B7"
+func validPins(pins []string) error {
+	for _, pin := range pins {
+		if len(pin) != 2 || (pin[0] != 'A' && pin[0] != 'B') || pin[1] < '0' || pin[1] > '7' {
+			return fmt.Errorf("sim: invalid pin %q", pin)
+		}
+	}
+	return nil
+}
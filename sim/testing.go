@@ -0,0 +1,22 @@
+package sim
+
+import (
+	"testing"
+
+	"github.com/jyap808/charLCDRGBI2C"
+)
+
+// NewLCD returns an LCD driving a new simulated board, for tests. The LCD
+// is closed, leaving the board as it is, when the test ends.
+func NewLCD(tb testing.TB, geometry charLCDRGBI2C.Geometry, wiring Wiring, opts ...charLCDRGBI2C.Option) (*charLCDRGBI2C.CharLCDRGBI2C, *Device) {
+	tb.Helper()
+	dev := New(geometry, wiring)
+	lcd, err := charLCDRGBI2C.NewWithDriver(dev, geometry, opts...)
+	if err != nil {
+		tb.Fatalf("NewWithDriver: %v", err)
+	}
+	tb.Cleanup(func() {
+		lcd.Close(charLCDRGBI2C.CloseOptions{})
+	})
+	return lcd, dev
+}
@@ -0,0 +1,178 @@
+package charLCDRGBI2C
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// State is everything the LCD shows, captured by Snapshot and put back by
+// Restore. It is a plain value that encodes to JSON or gob.
+type State struct {
+	Columns int `json:"columns"`
+	Lines   int `json:"lines"`
+
+	// Characters on each line as HD44780 character codes, like Text
+	Text []string `json:"text"`
+
+	// Custom character patterns by location, nil when never defined
+	Glyphs [8][]byte `json:"glyphs"`
+
+	CursorColumn int  `json:"cursor_column"` // Where the next Message starts
+	CursorRow    int  `json:"cursor_row"`
+	Cursor       bool `json:"cursor"`  // Cursor shown
+	Blink        bool `json:"blink"`   // Cursor blinking
+	Display      bool `json:"display"` // Display on
+
+	Direction   int  `json:"direction"` // LEFT_TO_RIGHT or RIGHT_TO_LEFT
+	ColumnAlign bool `json:"column_align"`
+
+	Color     [3]int `json:"color"` // RGB LED color (values from 0-100)
+	Backlight bool   `json:"backlight"`
+}
+
+// Snapshotter is a Display whose complete state can be saved and put back
+type Snapshotter interface {
+	Snapshot() State
+	Restore(state State) error
+}
+
+var _ Snapshotter = (*CharLCDRGBI2C)(nil)
+
+// Snapshot captures the text, custom characters, cursor, LED and backlight
+func (lcd *CharLCDRGBI2C) Snapshot() State {
+	lcd.lock()
+	defer lcd.unlock()
+	return lcd.snapshot()
+}
+
+// snapshot captures the state of the LCD
+func (lcd *CharLCDRGBI2C) snapshot() State {
+	state := State{
+		Columns:      lcd.columns,
+		Lines:        lcd.lines,
+		Text:         make([]string, len(lcd.fb.cells)),
+		Cursor:       lcd.displayControl&LCD_CURSORON != 0,
+		Blink:        lcd.displayControl&LCD_BLINKON != 0,
+		Display:      lcd.displayControl&LCD_DISPLAYON != 0,
+		Direction:    lcd.direction,
+		ColumnAlign:  lcd.columnAlign,
+		CursorColumn: lcd.column,
+		CursorRow:    lcd.row,
+		Color:        lcd.colorValue,
+		Backlight:    lcd.backlight,
+	}
+	for row, line := range lcd.fb.cells {
+		state.Text[row] = codes(line)
+	}
+	for location, pattern := range lcd.glyphs {
+		if pattern != nil {
+			state.Glyphs[location] = append([]byte(nil), pattern...)
+		}
+	}
+	return state
+}
+
+// Restore puts a snapshot back on the display. Only the characters, custom
+// characters and settings that differ from what is shown are sent.
+func (lcd *CharLCDRGBI2C) Restore(state State) error {
+	lcd.lock()
+	defer lcd.unlock()
+	return lcd.restore(state)
+}
+
+// restore puts a snapshot back on the display
+func (lcd *CharLCDRGBI2C) restore(state State) error {
+	if err := lcd.checkState(state); err != nil {
+		return err
+	}
+
+	for location, pattern := range state.Glyphs {
+		if pattern != nil && !bytes.Equal(lcd.glyphs[location], pattern) {
+			lcd.createChar(byte(location), pattern)
+		}
+	}
+
+	// Write changed characters left to right, from the first to the last
+	// difference on each line
+	if lcd.displayMode&LCD_ENTRYLEFT == 0 {
+		lcd.leftToRight()
+	}
+	for row, text := range state.Text {
+		line := make([]byte, lcd.columns)
+		for column := range line {
+			line[column] = ' '
+		}
+		for column, character := range []rune(text) {
+			if column < lcd.columns {
+				line[column] = byte(character)
+			}
+		}
+
+		first, last := -1, -1
+		for column, character := range line {
+			if character != lcd.fb.cells[row][column] {
+				if first < 0 {
+					first = column
+				}
+				last = column
+			}
+		}
+		if first < 0 {
+			continue
+		}
+		lcd.cursorPosition(first, row)
+		for _, character := range line[first : last+1] {
+			lcd.writeChar(character)
+		}
+	}
+
+	lcd.direction = state.Direction
+	if state.Direction == RIGHT_TO_LEFT {
+		lcd.rightToLeft()
+	}
+	lcd.columnAlign = state.ColumnAlign
+
+	control := byte(0)
+	if state.Display {
+		control |= LCD_DISPLAYON
+	}
+	if state.Cursor {
+		control |= LCD_CURSORON
+	}
+	if state.Blink {
+		control |= LCD_BLINKON
+	}
+	// Leave the cursor where the next Message starts, not after the text
+	// just redrawn
+	lcd.cursorPosition(state.CursorColumn, state.CursorRow)
+	if control != lcd.displayControl {
+		lcd.displayControl = control
+		lcd.updateDisplayControl()
+	}
+
+	if state.Color != lcd.colorValue {
+		lcd.setColor(state.Color[0], state.Color[1], state.Color[2])
+	}
+	if state.Backlight != lcd.backlight {
+		return lcd.setBacklight(state.Backlight)
+	}
+	return nil
+}
+
+// checkState checks that a snapshot was taken of a display like this one
+func (lcd *CharLCDRGBI2C) checkState(state State) error {
+	if state.Columns != lcd.columns || state.Lines != lcd.lines || len(state.Text) != lcd.lines {
+		return fmt.Errorf("snapshot of a %dx%d display can not be restored on a %dx%d display",
+			state.Columns, state.Lines, lcd.columns, lcd.lines)
+	}
+	return nil
+}
+
+// codes turns HD44780 character codes into a string Message writes back
+func codes(line []byte) string {
+	runes := make([]rune, len(line))
+	for i, code := range line {
+		runes[i] = rune(code)
+	}
+	return string(runes)
+}
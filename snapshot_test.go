@@ -0,0 +1,34 @@
+package charLCDRGBI2C_test
+
+import (
+	"testing"
+
+	"github.com/jyap808/charLCDRGBI2C"
+	"github.com/jyap808/charLCDRGBI2C/sim"
+)
+
+func TestRestoreKeepsMessagePosition(t *testing.T) {
+	lcd, dev := sim.NewLCD(t, charLCDRGBI2C.Geometry16x2, sim.Wiring{})
+
+	lcd.Message("Hello")
+	state := lcd.Snapshot()
+	lcd.Clear()
+	if err := lcd.Restore(state); err != nil {
+		t.Fatal(err)
+	}
+	lcd.Message("J")
+	if got := string(dev.Codes()[0]); got != "Jello           " {
+		t.Errorf("line 0 shows %q after Restore, want the next Message at 0,0", got)
+	}
+
+	lcd.CursorPosition(3, 1)
+	state = lcd.Snapshot()
+	lcd.Message("abc")
+	if err := lcd.Restore(state); err != nil {
+		t.Fatal(err)
+	}
+	lcd.Message("xyz")
+	if got := string(dev.Codes()[1]); got != "   xyz          " {
+		t.Errorf("line 1 shows %q after Restore, want the next Message at 3,1", got)
+	}
+}
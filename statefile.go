@@ -0,0 +1,193 @@
+package charLCDRGBI2C
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// stateSaveDelay is how long after a change the state file is written, so
+// that a burst of updates is saved once
+const stateSaveDelay = time.Second
+
+// SaveState writes a snapshot of the display to path as JSON. The file is
+// replaced atomically, a crash never leaves half a state behind.
+func (lcd *CharLCDRGBI2C) SaveState(path string) error {
+	lcd.lock()
+	defer lcd.unlock()
+	return saveState(path, lcd.snapshot())
+}
+
+// LoadState reads a state written by SaveState
+func LoadState(path string) (State, error) {
+	var state State
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return state, err
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, err
+	}
+	return state, nil
+}
+
+// saveState writes state to a temporary file and renames it over path
+func saveState(path string, state State) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return writeState(path, data)
+}
+
+// writeState writes data to a temporary file and renames it over path
+func writeState(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// scheduleSave saves the state file after stateSaveDelay, unless a save is
+// already pending. lcd.mu must be held.
+func (lcd *CharLCDRGBI2C) scheduleSave() {
+	if lcd.stateFile == "" || lcd.saveTimer != nil {
+		return
+	}
+	lcd.saveTimer = time.AfterFunc(stateSaveDelay, lcd.saveChanged)
+}
+
+// saveChanged writes the state file when the state differs from what was
+// written last
+func (lcd *CharLCDRGBI2C) saveChanged() {
+	lcd.mu.Lock()
+	defer lcd.mu.Unlock()
+	lcd.saveTimer = nil
+	select {
+	case <-lcd.done:
+		// Close saves the final state
+		return
+	default:
+	}
+
+	data, err := json.Marshal(lcd.snapshot())
+	if err == nil && !bytes.Equal(data, lcd.saved) {
+		if err = writeState(lcd.stateFile, data); err == nil {
+			lcd.saved = data
+		}
+	}
+	if err != nil {
+		log.Printf("Error saving state file %s: %v", lcd.stateFile, err)
+	}
+}
+
+// loadSavedState reads the state file, nil when there is none or it does
+// not fit the display
+func (lcd *CharLCDRGBI2C) loadSavedState() (*State, error) {
+	if lcd.stateFile == "" {
+		return nil, nil
+	}
+	state, err := LoadState(lcd.stateFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := lcd.checkState(state); err != nil {
+		// Saved by another panel, start afresh
+		log.Printf("Ignoring state file %s: %v", lcd.stateFile, err)
+		return nil, nil
+	}
+	return &state, nil
+}
+
+// configured reports whether the LCD pins are still set up as outputs by a
+// previous process, which means the board has not been power cycled
+func (lcd *CharLCDRGBI2C) configured() bool {
+	reader, ok := lcd.pins.(DirectionReader)
+	if !ok {
+		return false
+	}
+
+	pins := lcd.lcdPins()
+	outputs, err := reader.Outputs(pins...)
+	if err != nil {
+		return false
+	}
+	for _, pin := range pins {
+		if !outputs[pin] {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesBoard reports whether the LED and backlight pins are set the way
+// state has them. The LCD can not be read back, a board that differs here
+// was changed after state was saved and may show newer text.
+func (lcd *CharLCDRGBI2C) matchesBoard(state State) bool {
+	if pins := lcd.ledPins(); len(pins) > 0 {
+		levels, err := lcd.pins.Read(pins...)
+		if err != nil {
+			return false
+		}
+		for i, pin := range lcd.rgb {
+			// LOW = on for common anode RGB LED, see setColor
+			if pin != "" && (levels[pin] == 0) != (state.Color[i] > 1) {
+				return false
+			}
+		}
+	}
+
+	reader, ok := lcd.pins.(DirectionReader)
+	if !ok {
+		return false
+	}
+	if lcd.backlightPin == "" {
+		return true
+	}
+	outputs, err := reader.Outputs(lcd.backlightPin)
+	return err == nil && outputs[lcd.backlightPin] == state.Backlight
+}
+
+// lcdPins returns the pins setupPins makes outputs to drive the LCD
+func (lcd *CharLCDRGBI2C) lcdPins() []string {
+	pins := append([]string{LcdRsPin, RwPin}, lcd.enablePins...)
+	return append(pins, lcd.dataPins...)
+}
+
+// adopt takes over a display that still shows state. The controllers are
+// resynced to the bus without clearing them, then the text, custom
+// characters and settings of state are written over what they show.
+func (lcd *CharLCDRGBI2C) adopt(state State) error {
+	lcd.displayControl = LCD_DISPLAYON
+	lcd.displayMode = LCD_ENTRYLEFT | LCD_ENTRYSHIFTDECREMENT
+	lcd.resync()
+
+	// DDRAM may hold text written after state was saved, and can not be read
+	// back. Writing every line again doesn't show where it is the same.
+	for row, text := range state.Text {
+		for column, character := range []rune(text) {
+			if column < lcd.columns {
+				lcd.fb.cells[row][column] = byte(character)
+			}
+		}
+		lcd.drawLine(row)
+	}
+	return lcd.restore(state)
+}
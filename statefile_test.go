@@ -0,0 +1,87 @@
+package charLCDRGBI2C_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jyap808/charLCDRGBI2C"
+	"github.com/jyap808/charLCDRGBI2C/sim"
+)
+
+func TestStateSavedOnChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lcd.json")
+	dev := sim.New(charLCDRGBI2C.Geometry16x2, sim.Wiring{})
+	lcd, err := charLCDRGBI2C.NewWithDriver(dev, charLCDRGBI2C.Geometry16x2, charLCDRGBI2C.WithStateFile(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lcd.Message("Hello")
+
+	// Saved without Close, as the process may be killed
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		state, err := charLCDRGBI2C.LoadState(path)
+		if err == nil && strings.HasPrefix(state.Text[0], "Hello") {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("state file not saved after a change: %v", err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	lcd, err = charLCDRGBI2C.NewWithDriver(dev, charLCDRGBI2C.Geometry16x2, charLCDRGBI2C.WithStateFile(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lcd.Close(charLCDRGBI2C.CloseOptions{})
+	if got := lcd.Text()[0]; got != "Hello           " {
+		t.Errorf("line 0 is %q after a restart", got)
+	}
+}
+
+func TestStaleStateFile(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(lcd *charLCDRGBI2C.CharLCDRGBI2C)
+	}{
+		{"text", func(lcd *charLCDRGBI2C.CharLCDRGBI2C) {
+			lcd.Message("Newer")
+		}},
+		{"text and LED", func(lcd *charLCDRGBI2C.CharLCDRGBI2C) {
+			lcd.Message("Newer")
+			lcd.SetColor(100, 0, 0)
+		}},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "lcd.json")
+		lcd, dev := sim.NewLCD(t, charLCDRGBI2C.Geometry16x2, sim.Wiring{})
+		lcd.SetColor(0, 0, 100)
+		lcd.Message("Hello")
+		if err := lcd.SaveState(path); err != nil {
+			t.Fatal(err)
+		}
+		// Changed after the last save, then killed
+		tt.change(lcd)
+
+		lcd, err := charLCDRGBI2C.NewWithDriver(dev, charLCDRGBI2C.Geometry16x2, charLCDRGBI2C.WithStateFile(path))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer lcd.Close(charLCDRGBI2C.CloseOptions{})
+		text := lcd.Text()
+		for row, line := range dev.Codes() {
+			if string(line) != text[row] {
+				t.Errorf("%s: line %d shows %q, driver has %q", tt.name, row, line, text[row])
+			}
+		}
+		if text[0] != "Hello           " {
+			t.Errorf("%s: line 0 is %q, want the saved text", tt.name, text[0])
+		}
+		if red, green, blue := dev.LED(); red || green || !blue {
+			t.Errorf("%s: LED red %v green %v blue %v, want blue", tt.name, red, green, blue)
+		}
+	}
+}
@@ -0,0 +1,259 @@
+package charLCDRGBI2C
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Escape sequence parser states
+const (
+	termText   = iota // Plain text
+	termEscape        // After ESC
+	termCSI           // After ESC [
+)
+
+// ANSI colors 0-7 as RGB LED values
+var ansiColors = [8][3]int{
+	{0, 0, 0},       // Black
+	{100, 0, 0},     // Red
+	{0, 100, 0},     // Green
+	{100, 100, 0},   // Yellow
+	{0, 0, 100},     // Blue
+	{100, 0, 100},   // Magenta
+	{0, 100, 100},   // Cyan
+	{100, 100, 100}, // White
+}
+
+// terminal holds the io.Writer cursor and escape sequence parser state
+type terminal struct {
+	row    int    // Cursor row
+	column int    // Cursor column, equals the columns when a wrap is pending
+	state  int    // Escape sequence parser state
+	params []byte // Parameter bytes of the current control sequence
+}
+
+// Write implements io.Writer, treating the display as a small terminal
+//
+// Bytes are written as HD44780 character codes. '\n' moves to the start of
+// the next line, '\r' to the start of the current line, '\b' back one
+// column and '\f' clears the display. Output running past the bottom row
+// scrolls the display up one line.
+//
+// The ANSI control sequences CSI row;column H (cursor position), CSI n K
+// (erase line), CSI 2 J (erase display) and CSI n m (SGR) are understood,
+// with SGR foreground colors setting the RGB LED. Write expects the text
+// direction to be LEFT_TO_RIGHT.
+//
+// The terminal keeps its own cursor, a position set with CursorPosition
+// for the next Message is left as it is. Write stops at the first bus
+// error and returns it.
+func (lcd *CharLCDRGBI2C) Write(p []byte) (int, error) {
+	lcd.lock()
+	defer lcd.unlock()
+
+	row, column := lcd.row, lcd.column
+	defer func() {
+		lcd.row, lcd.column = row, column
+	}()
+
+	for i, b := range p {
+		lcd.termByte(b)
+		if err := lcd.recovery.err; err != nil {
+			return i, err
+		}
+	}
+	return len(p), nil
+}
+
+// termByte interprets one byte written to the terminal
+func (lcd *CharLCDRGBI2C) termByte(b byte) {
+	t := &lcd.term
+
+	switch t.state {
+	case termEscape:
+		if b == '[' {
+			t.state = termCSI
+			t.params = t.params[:0]
+		} else {
+			// Unsupported escape sequence
+			t.state = termText
+		}
+		return
+	case termCSI:
+		// Parameter and intermediate bytes until the final byte
+		if b >= 0x40 && b <= 0x7E {
+			lcd.termControl(b, parseParams(t.params))
+			t.state = termText
+		} else {
+			t.params = append(t.params, b)
+		}
+		return
+	}
+
+	switch b {
+	case 0x1B:
+		t.state = termEscape
+	case '\n':
+		lcd.termNewline()
+	case '\r':
+		t.column = 0
+	case '\b':
+		if t.column >= lcd.columns {
+			t.column = lcd.columns - 1
+		}
+		if t.column > 0 {
+			t.column--
+		}
+	case '\f':
+		lcd.clear()
+		t.row, t.column = 0, 0
+	default:
+		// Codes 0x00-0x07 are the custom characters, ignore other controls
+		if b >= 0x08 && b < 0x20 {
+			return
+		}
+		lcd.termPut(b)
+	}
+}
+
+// termPut writes a character at the terminal cursor
+func (lcd *CharLCDRGBI2C) termPut(character byte) {
+	t := &lcd.term
+	if t.column >= lcd.columns {
+		lcd.termNewline()
+	}
+	if !lcd.fb.at(t.column, t.row) {
+		lcd.cursorPosition(t.column, t.row)
+	}
+	lcd.writeChar(character)
+	t.column++
+}
+
+// termNewline moves the terminal cursor to the start of the next line,
+// scrolling when it is on the bottom row
+func (lcd *CharLCDRGBI2C) termNewline() {
+	t := &lcd.term
+	t.column = 0
+	if t.row < lcd.lines-1 {
+		t.row++
+		return
+	}
+	lcd.scroll()
+}
+
+// scroll moves every line up by one and blanks the bottom line
+func (lcd *CharLCDRGBI2C) scroll() {
+	lcd.fb.scroll()
+	for row := range lcd.fb.cells {
+		lcd.drawLine(row)
+	}
+}
+
+// termControl runs a CSI control sequence
+func (lcd *CharLCDRGBI2C) termControl(final byte, params []int) {
+	t := &lcd.term
+
+	switch final {
+	case 'H', 'f':
+		// Cursor position, 1-based
+		t.row = clamp(param(params, 0, 1)-1, 0, lcd.lines-1)
+		t.column = clamp(param(params, 1, 1)-1, 0, lcd.columns-1)
+	case 'K':
+		// Erase line
+		column := clamp(t.column, 0, lcd.columns-1)
+		switch param(params, 0, 0) {
+		case 0:
+			lcd.eraseLine(t.row, column, lcd.columns-1)
+		case 1:
+			lcd.eraseLine(t.row, 0, column)
+		case 2:
+			lcd.eraseLine(t.row, 0, lcd.columns-1)
+		}
+	case 'J':
+		// Erase display
+		if param(params, 0, 0) == 2 {
+			lcd.clear()
+		}
+	case 'm':
+		lcd.termSGR(params)
+	}
+}
+
+// eraseLine blanks the columns from and to of a row
+func (lcd *CharLCDRGBI2C) eraseLine(row, from, to int) {
+	lcd.cursorPosition(from, row)
+	for column := from; column <= to; column++ {
+		lcd.writeChar(' ')
+	}
+}
+
+// termSGR maps SGR color codes to the RGB LED
+func (lcd *CharLCDRGBI2C) termSGR(params []int) {
+	if len(params) == 0 {
+		params = []int{0}
+	}
+
+	for i := 0; i < len(params); i++ {
+		code := params[i]
+		switch {
+		case code <= 0, code == 39:
+			// Reset and default foreground turn the LED off
+			lcd.setColor(0, 0, 0)
+		case code >= 30 && code <= 37:
+			color := ansiColors[code-30]
+			lcd.setColor(color[0], color[1], color[2])
+		case code >= 90 && code <= 97:
+			color := ansiColors[code-90]
+			lcd.setColor(color[0], color[1], color[2])
+		case code == 38 && param(params, i+1, 0) == 5:
+			// 256 color palette, only the 16 basic colors are mapped
+			if index := param(params, i+2, 0); index < 16 {
+				color := ansiColors[index%8]
+				lcd.setColor(color[0], color[1], color[2])
+			}
+			i += 2
+		case code == 38 && param(params, i+1, 0) == 2:
+			// 24-bit color
+			r := clamp(param(params, i+2, 0), 0, 255)
+			g := clamp(param(params, i+3, 0), 0, 255)
+			b := clamp(param(params, i+4, 0), 0, 255)
+			lcd.setColorRGB(r<<16 | g<<8 | b) // Always 24 bits
+			i += 4
+		}
+	}
+}
+
+// parseParams splits CSI parameter bytes, missing parameters are -1
+func parseParams(raw []byte) []int {
+	if len(raw) == 0 {
+		return nil
+	}
+	var params []int
+	for _, field := range strings.Split(string(raw), ";") {
+		value, err := strconv.Atoi(field)
+		if err != nil {
+			value = -1
+		}
+		params = append(params, value)
+	}
+	return params
+}
+
+// param returns parameter i, or def when it is missing
+func param(params []int, i, def int) int {
+	if i < len(params) && params[i] >= 0 {
+		return params[i]
+	}
+	return def
+}
+
+// clamp limits value to the range lo to hi
+func clamp(value, lo, hi int) int {
+	if value < lo {
+		return lo
+	}
+	if value > hi {
+		return hi
+	}
+	return value
+}
@@ -0,0 +1,115 @@
+package charLCDRGBI2C_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jyap808/charLCDRGBI2C"
+	"github.com/jyap808/charLCDRGBI2C/sim"
+)
+
+// shows checks that the simulated panel and the driver both have want
+func shows(t *testing.T, lcd *charLCDRGBI2C.CharLCDRGBI2C, dev *sim.Device, want ...string) {
+	t.Helper()
+	text := lcd.Text()
+	for row, line := range dev.Codes() {
+		if string(line) != want[row] {
+			t.Errorf("line %d shows %q, want %q", row, line, want[row])
+		}
+		if text[row] != want[row] {
+			t.Errorf("line %d of Text() is %q, want %q", row, text[row], want[row])
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{"text", "Hello", []string{"Hello           ", "                "}},
+		{"newline", "Hello\nWorld", []string{"Hello           ", "World           "}},
+		{"carriage return", "Hello\rJ", []string{"Jello           ", "                "}},
+		{"backspace", "Hellp\bo", []string{"Hello           ", "                "}},
+		{"wrap", "0123456789abcdefXY", []string{"0123456789abcdef", "XY              "}},
+		{"scroll", "one\ntwo\nthree", []string{"two             ", "three           "}},
+		{"scroll on wrap", "one\n0123456789abcdefZ", []string{"0123456789abcdef", "Z               "}},
+		{"form feed", "one\ntwo\fthree", []string{"three           ", "                "}},
+		{"cursor position", "\x1b[2;5Hx\x1b[Hy", []string{"y               ", "    x           "}},
+		{"erase to end of line", "Hello\x1b[1;3H\x1b[K", []string{"He              ", "                "}},
+		{"erase to start of line", "Hello\x1b[1;3H\x1b[1K", []string{"   lo           ", "                "}},
+		{"erase line", "Hello\x1b[2K", []string{"                ", "                "}},
+		{"erase display", "Hello\nWorld\x1b[2J", []string{"                ", "                "}},
+		{"unsupported escape", "\x1b7Hi", []string{"Hi              ", "                "}},
+		{"custom character", "\x00\x07\x08", []string{"\x00\x07              ", "                "}},
+	}
+	for _, tt := range tests {
+		lcd, dev := sim.NewLCD(t, charLCDRGBI2C.Geometry16x2, sim.Wiring{})
+		n, err := fmt.Fprint(lcd, tt.input)
+		if n != len(tt.input) || err != nil {
+			t.Errorf("%s: Write returned %d, %v", tt.name, n, err)
+		}
+		t.Run(tt.name, func(t *testing.T) {
+			shows(t, lcd, dev, tt.want...)
+		})
+	}
+}
+
+func TestWriteColor(t *testing.T) {
+	tests := []struct {
+		input            string
+		red, green, blue bool
+	}{
+		{"\x1b[31m", true, false, false},
+		{"\x1b[1;32m", false, true, false},
+		{"\x1b[95m", true, false, true},
+		{"\x1b[38;5;6m", false, true, true},
+		{"\x1b[38;2;255;255;0m", true, true, false},
+		{"\x1b[33m\x1b[0m", false, false, false},
+		{"\x1b[36m\x1b[m", false, false, false},
+	}
+	for _, tt := range tests {
+		lcd, dev := sim.NewLCD(t, charLCDRGBI2C.Geometry16x2, sim.Wiring{})
+		fmt.Fprint(lcd, tt.input)
+		if red, green, blue := dev.LED(); red != tt.red || green != tt.green || blue != tt.blue {
+			t.Errorf("%q: LED red %v green %v blue %v, want %v %v %v", tt.input, red, green, blue, tt.red, tt.green, tt.blue)
+		}
+	}
+}
+
+// TestWriteKeepsMessagePosition checks that the terminal cursor and the
+// position set for the next Message do not move each other
+func TestWriteKeepsMessagePosition(t *testing.T) {
+	lcd, dev := sim.NewLCD(t, charLCDRGBI2C.Geometry16x2, sim.Wiring{})
+	lcd.CursorPosition(5, 1)
+	fmt.Fprint(lcd, "ab")
+	lcd.Message("M")
+	fmt.Fprint(lcd, "c")
+	shows(t, lcd, dev, "abc             ", "     M          ")
+}
+
+func TestWriteBusError(t *testing.T) {
+	lcd, dev := sim.NewLCD(t, charLCDRGBI2C.Geometry16x2, sim.Wiring{}, charLCDRGBI2C.WithRecovery(0, 0))
+	dev.Unplug()
+	n, err := fmt.Fprint(lcd, "Hello")
+	if !errors.Is(err, sim.ErrUnplugged) {
+		t.Errorf("Write returned %v, want %v", err, sim.ErrUnplugged)
+	}
+	if n != 0 {
+		t.Errorf("Write wrote %d bytes to an unplugged board", n)
+	}
+}
+
+// TestClearKeepsTextDirection checks that the entry mode Clear resets on
+// the controller is set again
+func TestClearKeepsTextDirection(t *testing.T) {
+	lcd, dev := sim.NewLCD(t, charLCDRGBI2C.Geometry16x2, sim.Wiring{})
+	lcd.SetTextDirection(charLCDRGBI2C.RIGHT_TO_LEFT)
+	lcd.Clear()
+	lcd.Message("xyz")
+	want := strings.Repeat(" ", 13) + "zyx"
+	shows(t, lcd, dev, want, strings.Repeat(" ", 16))
+}
@@ -0,0 +1,206 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jyap808/charLCDRGBI2C"
+)
+
+// First of the four custom character locations used by the progress bar
+const ProgressGlyph byte = 2
+
+// fullBlock is the solid block in the HD44780 character ROM
+const fullBlock = 0xFF
+
+// savedScreen is what a dialog puts back when it is dismissed.
+//
+// A Display that is not a charLCDRGBI2C.Snapshotter, such as a daemon or
+// gRPC client, can not be asked for its custom characters. Dialogs on it
+// put back the text and LED color only, CursorGlyph and the progress bar
+// glyphs from ProgressGlyph keep the dialog's patterns and have to be
+// defined again by the application if it uses those slots.
+type savedScreen struct {
+	text             []string
+	red, green, blue int
+	state            *charLCDRGBI2C.State // Full state of a Snapshotter
+}
+
+// saveScreen records the text and LED color of the display, or its full
+// state when it can take a snapshot
+func saveScreen(d charLCDRGBI2C.Display) savedScreen {
+	if s, ok := d.(charLCDRGBI2C.Snapshotter); ok {
+		state := s.Snapshot()
+		return savedScreen{state: &state}
+	}
+	red, green, blue := d.Color()
+	return savedScreen{text: d.Text(), red: red, green: green, blue: blue}
+}
+
+// restore puts the recorded screen back
+func (s savedScreen) restore(d charLCDRGBI2C.Display) {
+	if s.state != nil {
+		d.(charLCDRGBI2C.Snapshotter).Restore(*s.state)
+		return
+	}
+	for row, line := range s.text {
+		d.CursorPosition(0, row)
+		d.Message(line)
+	}
+	d.SetColor(s.red, s.green, s.blue)
+}
+
+// Confirm asks a yes or no question
+type Confirm struct {
+	Question string
+	Default  bool // Answer selected at first
+}
+
+// Run shows the question until Select confirms the answer selected with
+// Left and Right
+func (c *Confirm) Run(ctx context.Context, d charLCDRGBI2C.Display, events <-chan charLCDRGBI2C.ButtonEvent) (bool, error) {
+	defer saveScreen(d).restore(d)
+	d.CreateChar(CursorGlyph, cursorPattern)
+
+	answer := c.Default
+	row := promptRow(d, c.Question)
+	for {
+		yes, no := " Yes", " No"
+		if answer {
+			yes = string(rune(CursorGlyph)) + "Yes"
+		} else {
+			no = string(rune(CursorGlyph)) + "No"
+		}
+		writeLine(d, row, yes+"  "+no)
+
+		button, err := nextPress(ctx, events)
+		if err != nil {
+			return false, err
+		}
+
+		switch button {
+		case charLCDRGBI2C.LeftButton:
+			answer = true
+		case charLCDRGBI2C.RightButton:
+			answer = false
+		case charLCDRGBI2C.SelectButton:
+			return answer, nil
+		}
+	}
+}
+
+// Severity selects the LED color of an Alert
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+// LED colors by severity
+var severityColors = map[Severity][3]int{
+	SeverityInfo:    {0, 0, 100},
+	SeverityWarning: {100, 100, 0},
+	SeverityError:   {100, 0, 0},
+}
+
+// Alert shows a message with the LED set to the severity color
+type Alert struct {
+	Message  string        // Lines are separated by '\n'
+	Severity Severity      // Defaults to SeverityInfo
+	Timeout  time.Duration // Dismiss after the timeout, zero waits for Select
+}
+
+// Run shows the alert until Select is pressed or the timeout passes
+func (a *Alert) Run(ctx context.Context, d charLCDRGBI2C.Display, events <-chan charLCDRGBI2C.ButtonEvent) error {
+	defer saveScreen(d).restore(d)
+
+	_, lines := d.Size()
+	text := strings.Split(a.Message, "\n")
+	for row := 0; row < lines; row++ {
+		line := ""
+		if row < len(text) {
+			line = text[row]
+		}
+		writeLine(d, row, line)
+	}
+	color := severityColors[a.Severity]
+	d.SetColor(color[0], color[1], color[2])
+
+	waitCtx := ctx
+	if a.Timeout > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, a.Timeout)
+		defer cancel()
+	}
+	for {
+		button, err := nextPress(waitCtx, events)
+		if err != nil && ctx.Err() == nil && waitCtx.Err() != nil {
+			// Timed out
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if button == charLCDRGBI2C.SelectButton {
+			return nil
+		}
+	}
+}
+
+// Progress shows a title and a progress bar
+type Progress struct {
+	Title string // Shown on the first line when the display has more than one
+}
+
+// Run shows the progress bar, updated with every percentage received,
+// until percent is closed. The bar uses the four custom characters from
+// ProgressGlyph.
+func (p *Progress) Run(ctx context.Context, d charLCDRGBI2C.Display, percent <-chan int) error {
+	defer saveScreen(d).restore(d)
+
+	// Characters with 1 to 4 of the 5 pixel columns filled
+	for filled := 1; filled <= 4; filled++ {
+		row := byte(0x1F) &^ (0x1F >> filled)
+		d.CreateChar(ProgressGlyph+byte(filled-1), []byte{row, row, row, row, row, row, row, row})
+	}
+
+	row := promptRow(d, p.Title)
+	p.render(d, row, 0)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case value, ok := <-percent:
+			if !ok {
+				return nil
+			}
+			p.render(d, row, min(max(value, 0), 100))
+		}
+	}
+}
+
+// render draws the bar followed by the percentage
+func (p *Progress) render(d charLCDRGBI2C.Display, row, percent int) {
+	columns, _ := d.Size()
+	label := fmt.Sprintf(" %3d%%", percent)
+	width := columns - len(label)
+
+	// Each character is 5 pixel columns wide
+	pixels := percent * width * 5 / 100
+	bar := make([]rune, 0, width)
+	for i := 0; i < width; i++ {
+		switch filled := pixels - i*5; {
+		case filled >= 5:
+			bar = append(bar, fullBlock)
+		case filled > 0:
+			bar = append(bar, rune(ProgressGlyph)+rune(filled-1))
+		default:
+			bar = append(bar, ' ')
+		}
+	}
+	writeLine(d, row, string(bar)+label)
+}
@@ -0,0 +1,60 @@
+package ui_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jyap808/charLCDRGBI2C"
+	"github.com/jyap808/charLCDRGBI2C/sim"
+	"github.com/jyap808/charLCDRGBI2C/ui"
+)
+
+// glyphWatcher records the pattern of a custom character whenever a
+// message showing it is written
+type glyphWatcher struct {
+	*charLCDRGBI2C.CharLCDRGBI2C
+	dev   *sim.Device
+	glyph byte
+	shown [][]byte
+}
+
+func (w *glyphWatcher) Message(message string) {
+	w.CharLCDRGBI2C.Message(message)
+	if strings.ContainsRune(message, rune(w.glyph)) {
+		w.shown = append(w.shown, w.dev.Glyph(0, w.glyph))
+	}
+}
+
+func TestConfirm(t *testing.T) {
+	lcd, dev := sim.NewLCD(t, charLCDRGBI2C.Geometry16x2, sim.Wiring{})
+	full := []byte{0x1F, 0x1F, 0x1F, 0x1F, 0x1F, 0x1F, 0x1F, 0x1F}
+	lcd.CreateChar(ui.CursorGlyph, full)
+	lcd.Message("Before")
+
+	w := &glyphWatcher{CharLCDRGBI2C: lcd, dev: dev, glyph: ui.CursorGlyph}
+	confirm := ui.Confirm{Question: "Reboot?"}
+	events := taps(charLCDRGBI2C.LeftButton, charLCDRGBI2C.SelectButton)
+	answer, err := confirm.Run(context.Background(), w, events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !answer {
+		t.Error("got No, want Yes")
+	}
+
+	if len(w.shown) == 0 {
+		t.Fatal("Confirm never showed its cursor")
+	}
+	for _, pattern := range w.shown {
+		if string(pattern) == string(full) {
+			t.Error("Confirm shows its cursor without creating the glyph")
+		}
+	}
+	if got := dev.Glyph(0, ui.CursorGlyph); string(got) != string(full) {
+		t.Errorf("glyph %v after Confirm, want the previous one back", got)
+	}
+	if got := strings.TrimRight(lcd.Text()[0], " "); got != "Before" {
+		t.Errorf("line 0 is %q after Confirm, want %q", got, "Before")
+	}
+}
@@ -0,0 +1,153 @@
+package ui
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/jyap808/charLCDRGBI2C"
+)
+
+// IdleManager saves the panel when no button has been pressed for a while,
+// in up to three stages: dimming the LED, turning the backlight off and
+// blanking the display. Stages with a zero timeout are skipped.
+type IdleManager struct {
+	Dim       time.Duration // Idle time before dimming the LED
+	DimLevel  int           // LED brightness when dimmed, percent of the color
+	Backlight time.Duration // Idle time before turning the backlight off
+	Blank     time.Duration // Idle time before blanking the display
+}
+
+// idleStage is one step of going idle
+type idleStage struct {
+	after time.Duration
+	apply func()
+	undo  func() // Wakes up from apply
+}
+
+// Filter passes button events through, going idle when none arrive. The
+// press that wakes the panel up, and its release, are not passed on. The
+// returned channel is closed when events is closed or ctx is done.
+//
+// Waking up undoes the stages that were applied, leaving settings the
+// application changed while idle as they are. Displays that can not take a
+// snapshot are taken to be on when going idle.
+//
+// LEDs without PWM, like the one on this board, are either on or off so
+// dimming turns off colors that drop to 1 or below.
+func (im *IdleManager) Filter(ctx context.Context, d charLCDRGBI2C.Display, events <-chan charLCDRGBI2C.ButtonEvent) <-chan charLCDRGBI2C.ButtonEvent {
+	filtered := make(chan charLCDRGBI2C.ButtonEvent)
+
+	// State to restore on wake up, captured before the first stage
+	var red, green, blue int
+	var backlight, display bool
+	var dimmed [3]int // Color set by dimming
+
+	var stages []idleStage
+	if im.Dim > 0 {
+		stages = append(stages, idleStage{im.Dim, func() {
+			dimmed = [3]int{red * im.DimLevel / 100, green * im.DimLevel / 100, blue * im.DimLevel / 100}
+			d.SetColor(dimmed[0], dimmed[1], dimmed[2])
+		}, func() {
+			if r, g, b := d.Color(); [3]int{r, g, b} == dimmed {
+				d.SetColor(red, green, blue)
+			}
+		}})
+	}
+	if im.Backlight > 0 {
+		stages = append(stages, idleStage{im.Backlight, func() {
+			d.SetBacklight(false)
+		}, func() {
+			if backlight && !d.Backlight() {
+				d.SetBacklight(true)
+			}
+		}})
+	}
+	if im.Blank > 0 {
+		stages = append(stages, idleStage{im.Blank, func() {
+			if display {
+				d.SetDisplay(false)
+			}
+		}, func() {
+			if display {
+				d.SetDisplay(true)
+			}
+		}})
+	}
+	sort.SliceStable(stages, func(i, j int) bool {
+		return stages[i].after < stages[j].after
+	})
+
+	go func() {
+		defer close(filtered)
+
+		// Number of stages applied
+		applied := 0
+		// Button whose release belongs to the wake up press
+		var swallow string
+
+		var timer *time.Timer
+		var expired <-chan time.Time
+		if len(stages) > 0 {
+			timer = time.NewTimer(stages[0].after)
+			defer timer.Stop()
+			expired = timer.C
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-expired:
+				if applied == 0 {
+					red, green, blue = d.Color()
+					backlight = d.Backlight()
+					display = displayOn(d)
+				}
+				stages[applied].apply()
+				applied++
+				if applied < len(stages) {
+					timer.Reset(stages[applied].after - stages[applied-1].after)
+				}
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+
+				if event.Pressed && timer != nil {
+					timer.Reset(stages[0].after)
+				}
+				if event.Pressed && applied > 0 {
+					// Wake up in reverse order
+					for i := applied - 1; i >= 0; i-- {
+						stages[i].undo()
+					}
+					applied = 0
+					swallow = event.Button
+					continue
+				}
+				if !event.Pressed && event.Button == swallow {
+					swallow = ""
+					continue
+				}
+
+				select {
+				case filtered <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return filtered
+}
+
+// displayOn reports whether the display is on, displays that can not take
+// a snapshot are taken to be on
+func displayOn(d charLCDRGBI2C.Display) bool {
+	if s, ok := d.(charLCDRGBI2C.Snapshotter); ok {
+		return s.Snapshot().Display
+	}
+	return true
+}
@@ -0,0 +1,122 @@
+package ui_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jyap808/charLCDRGBI2C"
+	"github.com/jyap808/charLCDRGBI2C/sim"
+	"github.com/jyap808/charLCDRGBI2C/ui"
+)
+
+// idle runs im over a new simulated panel until the test ends. wake sends
+// a press that wakes the panel up and returns once it has.
+func idle(t *testing.T, im *ui.IdleManager) (lcd *charLCDRGBI2C.CharLCDRGBI2C, dev *sim.Device, wake func()) {
+	t.Helper()
+	lcd, dev = sim.NewLCD(t, charLCDRGBI2C.Geometry16x2, sim.Wiring{})
+	lcd.SetColor(100, 0, 100)
+	lcd.Message("Hello")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	events := make(chan charLCDRGBI2C.ButtonEvent)
+	filtered := im.Filter(ctx, lcd, events)
+
+	wake = func() {
+		t.Helper()
+		events <- charLCDRGBI2C.ButtonEvent{Button: select_, Pressed: true}
+		events <- charLCDRGBI2C.ButtonEvent{Button: select_}
+		// The wake up press is swallowed, this one is passed on after it
+		events <- charLCDRGBI2C.ButtonEvent{Button: up, Pressed: true}
+		if event := <-filtered; event.Button != up || !event.Pressed {
+			t.Errorf("got %+v after waking up, want the press of Up", event)
+		}
+	}
+	return lcd, dev, wake
+}
+
+// eventually waits for cond to hold
+func eventually(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestIdleStages(t *testing.T) {
+	lcd, dev, wake := idle(t, &ui.IdleManager{
+		Dim:       20 * time.Millisecond,
+		Backlight: 40 * time.Millisecond,
+		Blank:     60 * time.Millisecond,
+	})
+
+	eventually(t, "dimming", func() bool {
+		red, green, blue := dev.LED()
+		return !red && !green && !blue
+	})
+	eventually(t, "the backlight to turn off", func() bool { return !dev.Backlight() })
+	eventually(t, "blanking", func() bool { return !lcd.Snapshot().Display })
+
+	wake()
+	if red, green, blue := dev.LED(); !red || green || !blue {
+		t.Errorf("LED red %v green %v blue %v after waking up, want magenta", red, green, blue)
+	}
+	if !dev.Backlight() {
+		t.Error("backlight off after waking up")
+	}
+	if line := string(dev.Codes()[0]); line != "Hello           " {
+		t.Errorf("line 0 shows %q after waking up", line)
+	}
+}
+
+// TestIdleKeepsChanges checks that waking up leaves what the application
+// changed while idle, and what stages that did not run cover
+func TestIdleKeepsChanges(t *testing.T) {
+	t.Run("color set while dimmed", func(t *testing.T) {
+		lcd, dev, wake := idle(t, &ui.IdleManager{Dim: 10 * time.Millisecond})
+		eventually(t, "dimming", func() bool {
+			red, _, _ := dev.LED()
+			return !red
+		})
+		lcd.SetColor(0, 100, 0)
+		wake()
+		if red, green, blue := dev.LED(); red || !green || blue {
+			t.Errorf("LED red %v green %v blue %v, want green", red, green, blue)
+		}
+	})
+
+	t.Run("backlight turned off before its stage", func(t *testing.T) {
+		lcd, dev, wake := idle(t, &ui.IdleManager{Dim: 10 * time.Millisecond, Backlight: time.Hour})
+		eventually(t, "dimming", func() bool {
+			red, _, _ := dev.LED()
+			return !red
+		})
+		lcd.SetBacklight(false)
+		wake()
+		if dev.Backlight() {
+			t.Error("backlight turned on, it was not turned off by going idle")
+		}
+		if red, _, blue := dev.LED(); !red || !blue {
+			t.Error("LED not restored after dimming")
+		}
+	})
+
+	t.Run("display turned off before going idle", func(t *testing.T) {
+		lcd, dev, wake := idle(t, &ui.IdleManager{Backlight: 10 * time.Millisecond, Blank: 20 * time.Millisecond})
+		lcd.SetDisplay(false)
+		eventually(t, "the backlight to turn off", func() bool { return !dev.Backlight() })
+		time.Sleep(50 * time.Millisecond) // Blanking, which has nothing to do
+		wake()
+		if lcd.Snapshot().Display {
+			t.Error("display turned on, it was off before going idle")
+		}
+		if !dev.Backlight() {
+			t.Error("backlight off after waking up")
+		}
+	})
+}
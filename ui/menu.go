@@ -0,0 +1,229 @@
+package ui
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/jyap808/charLCDRGBI2C"
+)
+
+// Item is an entry of a Menu: *Menu, *Action, *Toggle, *Spinner or *Choice
+type Item interface {
+	Label() string
+}
+
+// Menu is a list of items, and an item itself when used as a submenu
+type Menu struct {
+	Title string
+	Items []Item
+}
+
+// Action is an item that ends the menu when selected
+type Action struct {
+	Name string
+	Do   func() error // Called when selected, may be nil
+}
+
+// Toggle is an on/off item, flipped with Right or Select
+type Toggle struct {
+	Name    string
+	On      bool
+	Changed func(on bool) // Called after every change, may be nil
+}
+
+// Spinner is a numeric item, edited with Up and Down after Select
+type Spinner struct {
+	Name    string
+	Value   int
+	Min     int             // Smallest value allowed
+	Max     int             // Largest value allowed, Min and Max both zero means no limits
+	Step    int             // Defaults to 1
+	Changed func(value int) // Called after every change, may be nil
+}
+
+// Choice is an enumeration item, edited with Up and Down after Select
+type Choice struct {
+	Name    string
+	Options []string
+	Index   int             // Selected option
+	Changed func(index int) // Called after every change, may be nil
+}
+
+func (m *Menu) Label() string    { return m.Title }
+func (a *Action) Label() string  { return a.Name }
+func (t *Toggle) Label() string  { return t.Name }
+func (s *Spinner) Label() string { return s.Name }
+func (c *Choice) Label() string  { return c.Name }
+
+// menuLevel is a menu being shown, with its scroll position
+type menuLevel struct {
+	menu     *Menu
+	selected int // Selected item
+	top      int // Item shown on the first line
+}
+
+// Run shows the menu until an Action is selected, which is returned after
+// its Do function has run.
+//
+// Up and Down move the selection, Right and Select enter submenus, flip
+// toggles and start editing spinners and choices, Left goes back to the
+// parent menu. Left on the top menu returns ErrCancelled.
+func (m *Menu) Run(ctx context.Context, d charLCDRGBI2C.Display, events <-chan charLCDRGBI2C.ButtonEvent) (Item, error) {
+	d.CreateChar(CursorGlyph, cursorPattern)
+	d.CreateChar(EditGlyph, editPattern)
+	d.SetCursor(false)
+	d.SetBlink(false)
+
+	stack := []*menuLevel{{menu: m}}
+	editing := false
+
+	for {
+		level := stack[len(stack)-1]
+		level.render(d, editing)
+
+		button, err := nextPress(ctx, events)
+		if err != nil {
+			return nil, err
+		}
+
+		var item Item
+		if level.selected < len(level.menu.Items) {
+			item = level.menu.Items[level.selected]
+		}
+
+		switch button {
+		case charLCDRGBI2C.UpButton:
+			if editing {
+				adjust(item, 1)
+			} else if level.selected > 0 {
+				level.selected--
+			}
+		case charLCDRGBI2C.DownButton:
+			if editing {
+				adjust(item, -1)
+			} else if level.selected < len(level.menu.Items)-1 {
+				level.selected++
+			}
+		case charLCDRGBI2C.RightButton, charLCDRGBI2C.SelectButton:
+			if editing {
+				editing = false
+				continue
+			}
+			switch item := item.(type) {
+			case *Menu:
+				if len(item.Items) > 0 {
+					stack = append(stack, &menuLevel{menu: item})
+				}
+			case *Action:
+				if item.Do != nil {
+					return item, item.Do()
+				}
+				return item, nil
+			case *Toggle:
+				item.On = !item.On
+				if item.Changed != nil {
+					item.Changed(item.On)
+				}
+			case *Spinner, *Choice:
+				editing = true
+			}
+		case charLCDRGBI2C.LeftButton:
+			switch {
+			case editing:
+				editing = false
+			case len(stack) > 1:
+				stack = stack[:len(stack)-1]
+			default:
+				return nil, ErrCancelled
+			}
+		}
+	}
+}
+
+// render draws the visible part of the menu, scrolled to the selection
+func (level *menuLevel) render(d charLCDRGBI2C.Display, editing bool) {
+	columns, lines := d.Size()
+
+	// Keep the selection inside the visible lines
+	if level.selected < level.top {
+		level.top = level.selected
+	}
+	if level.selected >= level.top+lines {
+		level.top = level.selected - lines + 1
+	}
+
+	for row := 0; row < lines; row++ {
+		i := level.top + row
+		if i >= len(level.menu.Items) {
+			writeLine(d, row, "")
+			continue
+		}
+
+		marker := " "
+		if i == level.selected {
+			marker = string(rune(CursorGlyph))
+			if editing {
+				marker = string(rune(EditGlyph))
+			}
+		}
+		writeLine(d, row, marker+itemText(level.menu.Items[i], columns-1))
+	}
+}
+
+// itemText formats an item as its label with its value right aligned
+func itemText(item Item, width int) string {
+	value := itemValue(item)
+	if value == "" {
+		return item.Label()
+	}
+	label := fit(item.Label(), width-len(value)-1)
+	return label + " " + value
+}
+
+// itemValue returns the value shown next to an item's label
+func itemValue(item Item) string {
+	switch item := item.(type) {
+	case *Menu:
+		return ">"
+	case *Toggle:
+		if item.On {
+			return "On"
+		}
+		return "Off"
+	case *Spinner:
+		return strconv.Itoa(item.Value)
+	case *Choice:
+		if item.Index >= 0 && item.Index < len(item.Options) {
+			return item.Options[item.Index]
+		}
+	}
+	return ""
+}
+
+// adjust steps the value of a spinner or choice up or down
+func adjust(item Item, direction int) {
+	switch item := item.(type) {
+	case *Spinner:
+		step := item.Step
+		if step == 0 {
+			step = 1
+		}
+		value := item.Value + direction*step
+		if (item.Min != 0 || item.Max != 0) && (value < item.Min || value > item.Max) {
+			return
+		}
+		item.Value = value
+		if item.Changed != nil {
+			item.Changed(item.Value)
+		}
+	case *Choice:
+		index := item.Index + direction
+		if index < 0 || index >= len(item.Options) {
+			return
+		}
+		item.Index = index
+		if item.Changed != nil {
+			item.Changed(item.Index)
+		}
+	}
+}
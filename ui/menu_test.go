@@ -0,0 +1,161 @@
+package ui_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jyap808/charLCDRGBI2C"
+	"github.com/jyap808/charLCDRGBI2C/sim"
+	"github.com/jyap808/charLCDRGBI2C/ui"
+)
+
+const (
+	up      = charLCDRGBI2C.UpButton
+	down    = charLCDRGBI2C.DownButton
+	left    = charLCDRGBI2C.LeftButton
+	right   = charLCDRGBI2C.RightButton
+	select_ = charLCDRGBI2C.SelectButton
+)
+
+// testMenu returns a menu with a submenu and one item of every kind
+func testMenu() (*ui.Menu, *ui.Toggle, *ui.Spinner, *ui.Choice) {
+	wifi := &ui.Toggle{Name: "WiFi"}
+	volume := &ui.Spinner{Name: "Volume", Value: 5, Min: 0, Max: 10}
+	mode := &ui.Choice{Name: "Mode", Options: []string{"Auto", "Heat", "Cool"}}
+	menu := &ui.Menu{Title: "Main", Items: []ui.Item{
+		&ui.Menu{Title: "Settings", Items: []ui.Item{wifi, volume, mode}},
+		&ui.Action{Name: "Reboot"},
+		&ui.Action{Name: "Exit"},
+	}}
+	return menu, wifi, volume, mode
+}
+
+func TestMenuNavigation(t *testing.T) {
+	tests := []struct {
+		name    string
+		buttons []string
+		want    string // Label of the Action selected
+		err     error
+	}{
+		{"first action", []string{down, select_}, "Reboot", nil},
+		{"last action", []string{down, down, down, select_}, "Exit", nil},
+		{"up stops at the top", []string{up, down, right}, "Reboot", nil},
+		{"back out of submenu", []string{right, down, left, down, down, select_}, "Exit", nil},
+		{"cancel", []string{right, left, left}, "", ui.ErrCancelled},
+		{"events closed", []string{down}, "", ui.ErrClosed},
+	}
+	for _, tt := range tests {
+		lcd, _ := sim.NewLCD(t, charLCDRGBI2C.Geometry16x2, sim.Wiring{})
+		menu, _, _, _ := testMenu()
+		item, err := menu.Run(context.Background(), lcd, taps(tt.buttons...))
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%s: got error %v, want %v", tt.name, err, tt.err)
+			continue
+		}
+		var got string
+		if item != nil {
+			got = item.Label()
+		}
+		if got != tt.want {
+			t.Errorf("%s: selected %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMenuDisplay(t *testing.T) {
+	lcd, dev := sim.NewLCD(t, charLCDRGBI2C.Geometry16x2, sim.Wiring{})
+	menu, _, _, _ := testMenu()
+	// Scroll down to the last two items
+	if _, err := menu.Run(context.Background(), lcd, taps(down, down)); !errors.Is(err, ui.ErrClosed) {
+		t.Fatal(err)
+	}
+	want := []string{" Reboot         ", "\x00Exit           "}
+	for row, line := range dev.Codes() {
+		if string(line) != want[row] {
+			t.Errorf("line %d shows %q, want %q", row, line, want[row])
+		}
+	}
+
+	// Values are right aligned next to their labels
+	lcd.Clear()
+	if _, err := menu.Run(context.Background(), lcd, taps(right, right)); !errors.Is(err, ui.ErrClosed) {
+		t.Fatal(err)
+	}
+	want = []string{"\x00WiFi         On", " Volume        5"}
+	for row, line := range dev.Codes() {
+		if string(line) != want[row] {
+			t.Errorf("line %d shows %q, want %q", row, line, want[row])
+		}
+	}
+}
+
+func TestMenuItems(t *testing.T) {
+	lcd, _ := sim.NewLCD(t, charLCDRGBI2C.Geometry16x2, sim.Wiring{})
+	menu, wifi, volume, mode := testMenu()
+
+	var changes []string
+	wifi.Changed = func(on bool) { changes = append(changes, "wifi") }
+	volume.Changed = func(value int) { changes = append(changes, "volume") }
+	mode.Changed = func(index int) { changes = append(changes, "mode") }
+
+	// Into Settings, WiFi on, off and on again, Volume up to 10 and no
+	// further, Mode down from the first option and up to Cool and no further,
+	// then back out to Exit
+	buttons := []string{
+		right,
+		select_, right, select_,
+		down, select_, up, up, up, up, up, up, left,
+		down, select_, down, up, up, up, select_,
+		left, down, down, select_,
+	}
+	item, err := menu.Run(context.Background(), lcd, taps(buttons...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.Label() != "Exit" {
+		t.Errorf("selected %q, want Exit", item.Label())
+	}
+	if !wifi.On {
+		t.Error("WiFi is off, want on")
+	}
+	if volume.Value != 10 {
+		t.Errorf("volume is %d, want 10", volume.Value)
+	}
+	if mode.Index != 2 {
+		t.Errorf("mode is %d, want 2", mode.Index)
+	}
+	want := "wifi wifi wifi volume volume volume volume volume mode mode"
+	if got := strings.Join(changes, " "); got != want {
+		t.Errorf("changes %q, want %q", got, want)
+	}
+}
+
+func TestSpinnerBounds(t *testing.T) {
+	tests := []struct {
+		name    string
+		spinner ui.Spinner
+		buttons []string
+		want    int
+	}{
+		{"unbounded", ui.Spinner{Value: 0}, []string{down, down, down}, -3},
+		{"step", ui.Spinner{Value: 0, Step: 5}, []string{up, up}, 10},
+		{"min", ui.Spinner{Value: 2, Min: 1, Max: 3}, []string{down, down, down}, 1},
+		{"max", ui.Spinner{Value: 2, Min: 1, Max: 3}, []string{up, up, up}, 3},
+		{"zero min", ui.Spinner{Value: 1, Min: 0, Max: 3}, []string{down, down}, 0},
+	}
+	for _, tt := range tests {
+		lcd, _ := sim.NewLCD(t, charLCDRGBI2C.Geometry16x2, sim.Wiring{})
+		tt.spinner.Name = "Value"
+		menu := &ui.Menu{Items: []ui.Item{&tt.spinner}}
+		buttons := append([]string{select_}, tt.buttons...)
+		if _, err := menu.Run(context.Background(), lcd, taps(buttons...)); !errors.Is(err, ui.ErrClosed) {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if tt.spinner.Value != tt.want {
+			t.Errorf("%s: value %d, want %d", tt.name, tt.spinner.Value, tt.want)
+		}
+	}
+}
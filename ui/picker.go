@@ -0,0 +1,302 @@
+package ui
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jyap808/charLCDRGBI2C"
+)
+
+// NumberPicker edits a number as a whole with Up and Down, stepping faster
+// while a button is held
+type NumberPicker struct {
+	Prompt   string  // Shown on the first line when the display has more than one
+	Value    float64 // Initial value
+	Min      float64 // Smallest value allowed
+	Max      float64 // Largest value allowed, Min and Max both zero means no limits
+	Step     float64 // Defaults to 1
+	Decimals int     // Digits shown after the decimal point
+	Unit     string  // Shown after the value, e.g. "°C"
+}
+
+// Run edits the value until Select confirms it. Left returns ErrCancelled.
+func (p *NumberPicker) Run(ctx context.Context, d charLCDRGBI2C.Display, events <-chan charLCDRGBI2C.ButtonEvent) (float64, error) {
+	step := p.Step
+	if step == 0 {
+		step = 1
+	}
+	value := p.clamp(p.Value)
+
+	d.CreateChar(EditGlyph, editPattern)
+	row := promptRow(d, p.Prompt)
+	var r repeater
+	for {
+		writeLine(d, row, string(rune(EditGlyph))+strconv.FormatFloat(value, 'f', p.Decimals, 64)+p.Unit)
+
+		button, count, err := r.next(ctx, events)
+		if err != nil {
+			return 0, err
+		}
+
+		switch button {
+		case charLCDRGBI2C.UpButton, charLCDRGBI2C.DownButton:
+			delta := step * float64(acceleration(count))
+			if button == charLCDRGBI2C.DownButton {
+				delta = -delta
+			}
+			// Round to the step to keep floating point error out of the value
+			value = math.Round((value+delta)/step) * step
+			value = p.clamp(value)
+		case charLCDRGBI2C.SelectButton:
+			if count == 0 {
+				return value, nil
+			}
+		case charLCDRGBI2C.LeftButton:
+			if count == 0 {
+				return 0, ErrCancelled
+			}
+		}
+	}
+}
+
+// clamp keeps value within Min and Max
+func (p *NumberPicker) clamp(value float64) float64 {
+	if p.Min == 0 && p.Max == 0 {
+		return value
+	}
+	return math.Min(math.Max(value, p.Min), p.Max)
+}
+
+// field is one numeric part of a multi-field picker
+type field struct {
+	value int
+	min   int
+	max   int
+	width int    // Digits shown, zero padded
+	sep   string // Shown after the field
+}
+
+// fieldPicker edits a row of numeric fields, one at a time
+type fieldPicker struct {
+	prompt   string
+	fields   []field
+	limit    func(fields []field)       // Updates limits that depend on other fields, may be nil
+	validate func(fields []field) error // Checked before returning, may be nil
+}
+
+// run edits the fields until Select confirms them. Left and Right move
+// between fields, Left on the first field returns ErrCancelled.
+func (p *fieldPicker) run(ctx context.Context, d charLCDRGBI2C.Display, events <-chan charLCDRGBI2C.ButtonEvent) error {
+	row := promptRow(d, p.prompt)
+	d.SetBlink(true)
+	defer d.SetBlink(false)
+
+	var r repeater
+	current := 0
+	problem := ""
+	for {
+		// Problems replace the prompt until the next press, on 1-line
+		// displays the fields
+		if row > 0 {
+			writeLine(d, 0, cmp.Or(problem, p.prompt))
+		}
+		if p.limit != nil {
+			p.limit(p.fields)
+		}
+		for i := range p.fields {
+			f := &p.fields[i]
+			f.value = min(max(f.value, f.min), f.max)
+		}
+
+		// Draw the fields and put the cursor on the last digit of the
+		// current one
+		var text strings.Builder
+		column := 0
+		for i, f := range p.fields {
+			fmt.Fprintf(&text, "%0*d", f.width, f.value)
+			if i == current {
+				column = text.Len() - 1
+			}
+			text.WriteString(f.sep)
+		}
+		if row == 0 && problem != "" {
+			writeLine(d, row, problem)
+		} else {
+			writeLine(d, row, text.String())
+			d.CursorPosition(column, row)
+		}
+		problem = ""
+
+		button, count, err := r.next(ctx, events)
+		if err != nil {
+			return err
+		}
+		if count > 0 && button != charLCDRGBI2C.UpButton && button != charLCDRGBI2C.DownButton {
+			continue
+		}
+
+		f := &p.fields[current]
+		switch button {
+		case charLCDRGBI2C.UpButton:
+			f.value = wrap(f.value+acceleration(count), f.min, f.max)
+		case charLCDRGBI2C.DownButton:
+			f.value = wrap(f.value-acceleration(count), f.min, f.max)
+		case charLCDRGBI2C.RightButton:
+			if current < len(p.fields)-1 {
+				current++
+			}
+		case charLCDRGBI2C.LeftButton:
+			if current == 0 {
+				return ErrCancelled
+			}
+			current--
+		case charLCDRGBI2C.SelectButton:
+			if p.validate == nil {
+				return nil
+			}
+			err := p.validate(p.fields)
+			if err == nil {
+				return nil
+			}
+			problem = err.Error()
+		}
+	}
+}
+
+// IPPicker edits an IPv4 address octet by octet
+type IPPicker struct {
+	Prompt string
+	Value  net.IP // Initial address, defaults to 0.0.0.0
+}
+
+// Run edits the address until Select confirms it
+func (p *IPPicker) Run(ctx context.Context, d charLCDRGBI2C.Display, events <-chan charLCDRGBI2C.ButtonEvent) (net.IP, error) {
+	ip := p.Value.To4()
+	if ip == nil {
+		ip = net.IPv4zero.To4()
+	}
+
+	picker := &fieldPicker{prompt: p.Prompt}
+	for i, octet := range ip {
+		sep := "."
+		if i == len(ip)-1 {
+			sep = ""
+		}
+		picker.fields = append(picker.fields, field{value: int(octet), max: 255, width: 3, sep: sep})
+	}
+	if err := picker.run(ctx, d, events); err != nil {
+		return nil, err
+	}
+
+	f := picker.fields
+	return net.IPv4(byte(f[0].value), byte(f[1].value), byte(f[2].value), byte(f[3].value)), nil
+}
+
+// DatePicker edits a date as year, month and day, keeping the time of day
+type DatePicker struct {
+	Prompt string
+	Value  time.Time // Initial date
+	Min    time.Time // Earliest date allowed, unchecked when zero
+	Max    time.Time // Latest date allowed, unchecked when zero
+}
+
+// Run edits the date until Select confirms a date within Min and Max
+func (p *DatePicker) Run(ctx context.Context, d charLCDRGBI2C.Display, events <-chan charLCDRGBI2C.ButtonEvent) (time.Time, error) {
+	value := p.Value
+	date := func(f []field) time.Time {
+		return time.Date(f[0].value, time.Month(f[1].value), f[2].value,
+			value.Hour(), value.Minute(), value.Second(), value.Nanosecond(), value.Location())
+	}
+
+	picker := &fieldPicker{
+		prompt: p.Prompt,
+		fields: []field{
+			{value: value.Year(), min: 1970, max: 2099, width: 4, sep: "-"},
+			{value: int(value.Month()), min: 1, max: 12, width: 2, sep: "-"},
+			{value: value.Day(), min: 1, max: 31, width: 2},
+		},
+		limit: func(f []field) {
+			// Days in the month, the day after the last one is the first of
+			// the next month
+			f[2].max = time.Date(f[0].value, time.Month(f[1].value)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+		},
+		validate: func(f []field) error {
+			// Only the dates count, not the time of day
+			t := dateOnly(date(f))
+			if !p.Min.IsZero() && t.Before(dateOnly(p.Min)) {
+				return fmt.Errorf("Min %s", p.Min.Format(time.DateOnly))
+			}
+			if !p.Max.IsZero() && t.After(dateOnly(p.Max)) {
+				return fmt.Errorf("Max %s", p.Max.Format(time.DateOnly))
+			}
+			return nil
+		},
+	}
+	if err := picker.run(ctx, d, events); err != nil {
+		return time.Time{}, err
+	}
+	return date(picker.fields), nil
+}
+
+// dateOnly returns midnight UTC of the date of t
+func dateOnly(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// TimePicker edits a time of day as hours, minutes and optionally seconds,
+// keeping the date
+type TimePicker struct {
+	Prompt  string
+	Value   time.Time // Initial time
+	Seconds bool      // Edit seconds as well
+}
+
+// Run edits the time until Select confirms it
+func (p *TimePicker) Run(ctx context.Context, d charLCDRGBI2C.Display, events <-chan charLCDRGBI2C.ButtonEvent) (time.Time, error) {
+	value := p.Value
+
+	picker := &fieldPicker{
+		prompt: p.Prompt,
+		fields: []field{
+			{value: value.Hour(), max: 23, width: 2, sep: ":"},
+			{value: value.Minute(), max: 59, width: 2},
+		},
+	}
+	if p.Seconds {
+		picker.fields[1].sep = ":"
+		picker.fields = append(picker.fields, field{value: value.Second(), max: 59, width: 2})
+	}
+	if err := picker.run(ctx, d, events); err != nil {
+		return time.Time{}, err
+	}
+
+	f := picker.fields
+	second := value.Second()
+	if p.Seconds {
+		second = f[2].value
+	}
+	return time.Date(value.Year(), value.Month(), value.Day(), f[0].value, f[1].value, second, 0, value.Location()), nil
+}
+
+// promptRow writes the prompt on the first line when the display has more
+// than one and returns the row to edit on
+func promptRow(d charLCDRGBI2C.Display, prompt string) int {
+	if _, lines := d.Size(); lines > 1 {
+		writeLine(d, 0, prompt)
+		return 1
+	}
+	return 0
+}
+
+// wrap keeps value within lo and hi, wrapping around at either end
+func wrap(value, lo, hi int) int {
+	span := hi - lo + 1
+	return lo + ((value-lo)%span+span)%span
+}
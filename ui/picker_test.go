@@ -0,0 +1,141 @@
+package ui_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jyap808/charLCDRGBI2C"
+	"github.com/jyap808/charLCDRGBI2C/sim"
+	"github.com/jyap808/charLCDRGBI2C/ui"
+)
+
+// taps returns button events pressing and releasing each button in turn,
+// the channel is closed after the last one
+func taps(buttons ...string) <-chan charLCDRGBI2C.ButtonEvent {
+	events := make(chan charLCDRGBI2C.ButtonEvent, 2*len(buttons))
+	now := time.Now()
+	for _, button := range buttons {
+		events <- charLCDRGBI2C.ButtonEvent{Button: button, Pressed: true, Time: now}
+		events <- charLCDRGBI2C.ButtonEvent{Button: button, Time: now}
+	}
+	close(events)
+	return events
+}
+
+func TestNumberPicker(t *testing.T) {
+	tests := []struct {
+		name    string
+		picker  ui.NumberPicker
+		buttons []string
+		want    float64
+	}{
+		{"unbounded", ui.NumberPicker{Value: 5}, []string{charLCDRGBI2C.UpButton}, 6},
+		{"below zero", ui.NumberPicker{Value: 0}, []string{charLCDRGBI2C.DownButton, charLCDRGBI2C.DownButton}, -2},
+		{"max", ui.NumberPicker{Value: 18.5, Min: 10, Max: 19, Step: 0.5, Decimals: 1}, []string{charLCDRGBI2C.UpButton, charLCDRGBI2C.UpButton}, 19},
+		{"initial value clamped", ui.NumberPicker{Value: 5, Min: 10, Max: 20}, nil, 10},
+	}
+	for _, tt := range tests {
+		lcd, _ := sim.NewLCD(t, charLCDRGBI2C.Geometry16x2, sim.Wiring{})
+		events := taps(append(tt.buttons, charLCDRGBI2C.SelectButton)...)
+		got, err := tt.picker.Run(context.Background(), lcd, events)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNumberPickerUploadsGlyph(t *testing.T) {
+	lcd, dev := sim.NewLCD(t, charLCDRGBI2C.Geometry16x2, sim.Wiring{})
+	full := []byte{0x1F, 0x1F, 0x1F, 0x1F, 0x1F, 0x1F, 0x1F, 0x1F}
+	lcd.CreateChar(ui.EditGlyph, full)
+
+	picker := ui.NumberPicker{Value: 1}
+	if _, err := picker.Run(context.Background(), lcd, taps(charLCDRGBI2C.SelectButton)); err != nil {
+		t.Fatal(err)
+	}
+	if string(dev.Glyph(0, ui.EditGlyph)) == string(full) {
+		t.Error("NumberPicker shows its marker without creating the glyph")
+	}
+	if got := dev.Codes()[1][0]; got != ui.EditGlyph {
+		t.Errorf("line 1 starts with %#x, want the edit glyph", got)
+	}
+}
+
+func TestDatePickerLimits(t *testing.T) {
+	noon := time.Date(2024, 3, 10, 12, 30, 0, 0, time.UTC)
+	midnight := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		picker  ui.DatePicker
+		buttons []string
+		want    time.Time
+	}{
+		{"max at midnight", ui.DatePicker{Value: noon, Max: midnight}, nil, noon},
+		{"min later that day", ui.DatePicker{Value: midnight, Min: noon}, nil, midnight},
+		{"past max", ui.DatePicker{Value: noon, Max: midnight}, []string{right, right, up, select_, down}, noon},
+	}
+	for _, tt := range tests {
+		lcd, _ := sim.NewLCD(t, charLCDRGBI2C.Geometry16x2, sim.Wiring{})
+		got, err := tt.picker.Run(context.Background(), lcd, taps(append(tt.buttons, select_)...))
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestPickerProblem checks that a value out of range is reported until the
+// next press, on the prompt line or on 1-line displays the only line
+func TestPickerProblem(t *testing.T) {
+	for _, geometry := range []charLCDRGBI2C.Geometry{charLCDRGBI2C.Geometry16x2, charLCDRGBI2C.Geometry16x1} {
+		lcd, dev := sim.NewLCD(t, geometry, sim.Wiring{})
+		picker := ui.DatePicker{
+			Prompt: "Date",
+			Value:  time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC),
+			Min:    time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC),
+		}
+		events := make(chan charLCDRGBI2C.ButtonEvent)
+		done := make(chan error)
+		go func() {
+			_, err := picker.Run(context.Background(), lcd, events)
+			done <- err
+		}()
+		tap := func(button string) {
+			events <- charLCDRGBI2C.ButtonEvent{Button: button, Pressed: true}
+			events <- charLCDRGBI2C.ButtonEvent{Button: button}
+		}
+		line := func() string {
+			// Taken once the previous press has been handled and drawn
+			events <- charLCDRGBI2C.ButtonEvent{}
+			return strings.TrimRight(string(dev.Codes()[0]), " ")
+		}
+
+		tap(select_)
+		if got := line(); got != "Min 2024-03-11" {
+			t.Errorf("%d lines: line 0 shows %q, want the problem", geometry.Lines, got)
+		}
+		tap(right)
+		want := "Date"
+		if geometry.Lines == 1 {
+			want = "2024-03-10"
+		}
+		if got := line(); got != want {
+			t.Errorf("%d lines: line 0 shows %q after the next press, want %q", geometry.Lines, got, want)
+		}
+		tap(up)
+		tap(right)
+		events <- charLCDRGBI2C.ButtonEvent{Button: select_, Pressed: true}
+		if err := <-done; err != nil {
+			t.Errorf("%d lines: %v", geometry.Lines, err)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"context"
+	"time"
+
+	"github.com/jyap808/charLCDRGBI2C"
+)
+
+// Held button repeat timing
+const (
+	repeatDelay    = 400 * time.Millisecond // Before the first repeat
+	repeatInterval = 100 * time.Millisecond // Between repeats
+)
+
+// repeater turns button presses into steps, repeating while a button is held
+type repeater struct {
+	held   string           // Button being held
+	count  int              // Repeats since the press
+	repeat <-chan time.Time // Next repeat of the held button
+}
+
+// next waits for the next press or repeat, count is 0 for a press and
+// counts up while the button stays held
+func (r *repeater) next(ctx context.Context, events <-chan charLCDRGBI2C.ButtonEvent) (button string, count int, err error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return "", 0, ctx.Err()
+		case event, ok := <-events:
+			if !ok {
+				return "", 0, ErrClosed
+			}
+			if event.Pressed {
+				r.held, r.count = event.Button, 0
+				r.repeat = time.After(repeatDelay)
+				return event.Button, 0, nil
+			}
+			if event.Button == r.held {
+				r.held, r.repeat = "", nil
+			}
+		case <-r.repeat:
+			r.count++
+			r.repeat = time.After(repeatInterval)
+			return r.held, r.count, nil
+		}
+	}
+}
+
+// acceleration returns the step multiplier after count repeats
+func acceleration(count int) int {
+	return 1 << min(count/8, 4)
+}
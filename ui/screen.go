@@ -0,0 +1,263 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jyap808/charLCDRGBI2C"
+)
+
+// Screen is a page of a Manager
+type Screen interface {
+	// Render draws the screen. It is called when the screen is shown, on
+	// every refresh and after every button press.
+	Render(d charLCDRGBI2C.Display)
+	// HandleButton is called for every button press while the screen is
+	// shown, returning false leaves the press to the manager.
+	HandleButton(m *Manager, button string) bool
+}
+
+// ColorScreen is a Screen that sets the RGB LED while it is shown
+type ColorScreen interface {
+	Screen
+	Color() (red, green, blue int)
+}
+
+// Manager owns the display and shows one screen at a time: the top of the
+// navigation stack, or the current page when the stack is empty. Pages
+// rotate on a timer while the stack is empty.
+//
+// Presses a screen does not handle navigate: Left and Right switch pages,
+// Left pops a pushed screen.
+//
+// Rotate and Refresh are set before Run, use SetRotate and SetRefresh
+// while it runs.
+type Manager struct {
+	Rotate  time.Duration // Time each page is shown for, zero disables rotation
+	Refresh time.Duration // Time between renders of the shown screen, zero disables
+
+	d       charLCDRGBI2C.Display
+	mu      sync.Mutex
+	names   map[string]int // Page index by name
+	pages   []Screen
+	page    int           // Current page
+	stack   []Screen      // Pushed screens
+	version int           // Counts changes of the shown screen
+	changed chan struct{} // Signals Run that the shown screen changed
+}
+
+// NewManager creates a screen manager for a display
+func NewManager(d charLCDRGBI2C.Display) *Manager {
+	return &Manager{
+		d:       d,
+		names:   make(map[string]int),
+		changed: make(chan struct{}, 1),
+	}
+}
+
+// Register adds a page to the end of the rotation
+func (m *Manager) Register(name string, s Screen) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if i, ok := m.names[name]; ok {
+		m.pages[i] = s
+	} else {
+		m.names[name] = len(m.pages)
+		m.pages = append(m.pages, s)
+	}
+	m.notify()
+}
+
+// Show switches to a registered page, dropping any pushed screens
+func (m *Manager) Show(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i, ok := m.names[name]
+	if !ok {
+		return fmt.Errorf("ui: no screen named %q", name)
+	}
+	m.page, m.stack = i, nil
+	m.notify()
+	return nil
+}
+
+// Push shows a screen on top of the current one, rotation pauses until it
+// is popped
+func (m *Manager) Push(s Screen) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.stack = append(m.stack, s)
+	m.notify()
+}
+
+// Pop goes back to the screen below the top of the stack
+func (m *Manager) Pop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.stack) > 0 {
+		m.stack = m.stack[:len(m.stack)-1]
+		m.notify()
+	}
+}
+
+// SetRotate changes the time each page is shown for, zero disables rotation
+func (m *Manager) SetRotate(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Rotate = d
+	m.notify()
+}
+
+// SetRefresh changes the time between renders of the shown screen, zero
+// disables
+func (m *Manager) SetRefresh(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Refresh = d
+	m.notify()
+}
+
+// Next switches to the next page
+func (m *Manager) Next() {
+	m.step(1)
+}
+
+// Previous switches to the previous page
+func (m *Manager) Previous() {
+	m.step(-1)
+}
+
+// step moves through the pages
+func (m *Manager) step(direction int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.pages) > 0 {
+		m.page = wrap(m.page+direction, 0, len(m.pages)-1)
+		m.notify()
+	}
+}
+
+// notify signals Run that the shown screen changed, m.mu must be held
+func (m *Manager) notify() {
+	m.version++
+	select {
+	case m.changed <- struct{}{}:
+	default:
+	}
+}
+
+// current returns the shown screen, nil when there is none, and the
+// version it was shown in
+func (m *Manager) current() (Screen, int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch {
+	case len(m.stack) > 0:
+		return m.stack[len(m.stack)-1], m.version
+	case len(m.pages) > 0:
+		return m.pages[m.page], m.version
+	}
+	return nil, m.version
+}
+
+// timing returns the time the shown page stays, zero when pages do not
+// rotate, and the time between refreshes
+func (m *Manager) timing() (rotate, refresh time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.stack) == 0 && len(m.pages) > 1 {
+		rotate = m.Rotate
+	}
+	return rotate, m.Refresh
+}
+
+// Run shows screens until ctx is done or events is closed
+func (m *Manager) Run(ctx context.Context, events <-chan charLCDRGBI2C.ButtonEvent) error {
+	var shown Screen
+	version := -1
+	var rotate, refresh <-chan time.Time
+
+	for {
+		rotateAfter, refreshAfter := m.timing()
+
+		// Draw the screen from scratch when it changed
+		if s, v := m.current(); v != version {
+			shown, version = s, v
+			m.d.Clear()
+			if shown != nil {
+				if cs, ok := shown.(ColorScreen); ok {
+					m.d.SetColor(cs.Color())
+				}
+				shown.Render(m.d)
+			}
+			rotate = nil
+			if rotateAfter > 0 {
+				rotate = time.After(rotateAfter)
+			}
+		}
+		if refreshAfter > 0 && refresh == nil {
+			refresh = time.After(refreshAfter)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-m.changed:
+		case <-rotate:
+			m.Next()
+		case <-refresh:
+			refresh = nil
+			if shown != nil {
+				shown.Render(m.d)
+			}
+		case event, ok := <-events:
+			if !ok {
+				return ErrClosed
+			}
+			if !event.Pressed || shown == nil {
+				continue
+			}
+			if shown.HandleButton(m, event.Button) {
+				// Screens that navigated are drawn from scratch instead
+				if _, v := m.current(); v == version {
+					shown.Render(m.d)
+				}
+				continue
+			}
+			m.navigate(event.Button)
+		}
+	}
+}
+
+// navigate handles a press the shown screen left to the manager
+func (m *Manager) navigate(button string) {
+	m.mu.Lock()
+	pushed := len(m.stack) > 0
+	m.mu.Unlock()
+
+	switch {
+	case button == charLCDRGBI2C.LeftButton && pushed:
+		m.Pop()
+	case button == charLCDRGBI2C.LeftButton:
+		m.Previous()
+	case button == charLCDRGBI2C.RightButton && !pushed:
+		m.Next()
+	}
+}
+
+// WriteLine writes text at the start of a row, padded or cut to the width
+// of the display, for screens to render with
+func WriteLine(d charLCDRGBI2C.Display, row int, text string) {
+	writeLine(d, row, text)
+}
@@ -0,0 +1,171 @@
+package ui_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jyap808/charLCDRGBI2C"
+	"github.com/jyap808/charLCDRGBI2C/sim"
+	"github.com/jyap808/charLCDRGBI2C/ui"
+)
+
+// page is a screen showing its name and counting its renders, Up is
+// handled by the screen itself
+type page struct {
+	name    string
+	renders atomic.Int32
+	ups     atomic.Int32
+}
+
+func (p *page) Render(d charLCDRGBI2C.Display) {
+	p.renders.Add(1)
+	ui.WriteLine(d, 0, p.name)
+}
+
+func (p *page) HandleButton(m *ui.Manager, button string) bool {
+	if button == up {
+		p.ups.Add(1)
+		return true
+	}
+	return false
+}
+
+// runManager runs m on lcd until the test ends, returning the channel
+// its button events are sent on
+func runManager(t *testing.T, m *ui.Manager) chan<- charLCDRGBI2C.ButtonEvent {
+	t.Helper()
+	events := make(chan charLCDRGBI2C.ButtonEvent)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- m.Run(ctx, events)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; !errors.Is(err, context.Canceled) {
+			t.Errorf("Run: %v", err)
+		}
+	})
+	return events
+}
+
+// waitShown waits for the first line of the panel to show name
+func waitShown(t *testing.T, dev *sim.Device, name string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		got := strings.TrimRight(string(dev.Codes()[0]), " ")
+		if got == name {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("line 0 shows %q, want %q", got, name)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestManagerNavigation(t *testing.T) {
+	lcd, dev := sim.NewLCD(t, charLCDRGBI2C.Geometry16x2, sim.Wiring{})
+	m := ui.NewManager(lcd)
+	home, status := &page{name: "home"}, &page{name: "status"}
+	m.Register("home", home)
+	m.Register("status", status)
+	events := runManager(t, m)
+	press := func(button string) {
+		events <- charLCDRGBI2C.ButtonEvent{Button: button, Pressed: true}
+	}
+
+	waitShown(t, dev, "home")
+	press(right)
+	waitShown(t, dev, "status")
+	press(right)
+	waitShown(t, dev, "home")
+	press(left)
+	waitShown(t, dev, "status")
+
+	// Pushed screens stack on top of the page, Left pops them
+	first, second := &page{name: "first"}, &page{name: "second"}
+	m.Push(first)
+	waitShown(t, dev, "first")
+	m.Push(second)
+	waitShown(t, dev, "second")
+	press(right) // Pages do not switch under a pushed screen
+	press(left)
+	waitShown(t, dev, "first")
+	m.Pop()
+	waitShown(t, dev, "status")
+	m.Pop() // Nothing to pop
+	press(up)
+	waitShown(t, dev, "status")
+	if status.ups.Load() != 1 {
+		t.Errorf("status page handled %d presses of Up, want 1", status.ups.Load())
+	}
+
+	// Show drops the pushed screens
+	m.Push(first)
+	waitShown(t, dev, "first")
+	if err := m.Show("home"); err != nil {
+		t.Fatal(err)
+	}
+	waitShown(t, dev, "home")
+	press(left)
+	waitShown(t, dev, "status")
+	if err := m.Show("missing"); err == nil {
+		t.Error("Show of an unregistered page returned no error")
+	}
+}
+
+func TestManagerRotation(t *testing.T) {
+	lcd, dev := sim.NewLCD(t, charLCDRGBI2C.Geometry16x2, sim.Wiring{})
+	m := ui.NewManager(lcd)
+	m.Register("one", &page{name: "one"})
+	m.Register("two", &page{name: "two"})
+	m.Rotate = 50 * time.Millisecond
+	runManager(t, m)
+
+	waitShown(t, dev, "one")
+	waitShown(t, dev, "two")
+	waitShown(t, dev, "one")
+
+	// Rotation pauses while a screen is pushed
+	m.Push(&page{name: "pushed"})
+	waitShown(t, dev, "pushed")
+	time.Sleep(200 * time.Millisecond)
+	waitShown(t, dev, "pushed")
+	m.Pop()
+	waitShown(t, dev, "one")
+	waitShown(t, dev, "two")
+
+	// And stops without a rotation time
+	m.SetRotate(0)
+	time.Sleep(200 * time.Millisecond)
+	shown := dev.Codes()[0]
+	for range 20 {
+		time.Sleep(10 * time.Millisecond)
+		if line := dev.Codes()[0]; string(line) != string(shown) {
+			t.Fatalf("line 0 changed from %q to %q with rotation off", shown, line)
+		}
+	}
+}
+
+func TestManagerRefresh(t *testing.T) {
+	lcd, _ := sim.NewLCD(t, charLCDRGBI2C.Geometry16x2, sim.Wiring{})
+	m := ui.NewManager(lcd)
+	p := &page{name: "clock"}
+	m.Register("clock", p)
+	runManager(t, m)
+
+	m.SetRefresh(20 * time.Millisecond)
+	deadline := time.Now().Add(5 * time.Second)
+	for p.renders.Load() < 5 {
+		if time.Now().After(deadline) {
+			t.Fatalf("rendered %d times, want refreshes", p.renders.Load())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
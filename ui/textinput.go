@@ -0,0 +1,141 @@
+package ui
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jyap808/charLCDRGBI2C"
+)
+
+// Character sets for TextInput
+const (
+	CharsetDigits    = "0123456789"
+	CharsetHex       = "0123456789ABCDEF"
+	CharsetHostname  = "abcdefghijklmnopqrstuvwxyz0123456789-."
+	CharsetPrintable = " !\"#$%&'()*+,-./0123456789:;<=>?@ABCDEFGHIJKLMNOPQRSTUVWXYZ[\\]^_`abcdefghijklmnopqrstuvwxyz{|}~"
+)
+
+// TextInput edits a string with the buttons
+type TextInput struct {
+	Prompt    string // Shown on the first line when the display has more than one
+	Charset   string // Characters cycled with Up and Down, defaults to CharsetPrintable
+	Value     string // Initial value
+	MaxLength int    // Defaults to the width of the display
+	Mask      bool   // Show '*' for every character but the one being edited
+}
+
+// Run edits the value until Select confirms it, returning the entered
+// string.
+//
+// Up and Down cycle the character under the cursor through the charset, at
+// the end of the text they add a character and cycling the last character
+// past the end of the charset removes it. Left and Right move the cursor,
+// Left on the first character returns ErrCancelled.
+func (t *TextInput) Run(ctx context.Context, d charLCDRGBI2C.Display, events <-chan charLCDRGBI2C.ButtonEvent) (string, error) {
+	columns, lines := d.Size()
+
+	charset := t.Charset
+	if charset == "" {
+		charset = CharsetPrintable
+	}
+	maxLength := t.MaxLength
+	if maxLength <= 0 {
+		maxLength = columns
+	}
+
+	buf := []byte(t.Value)
+	if len(buf) > maxLength {
+		buf = buf[:maxLength]
+	}
+	pos := 0
+
+	// The prompt gets the first line when there is room for it
+	row := 0
+	if lines > 1 {
+		row = 1
+		writeLine(d, 0, t.Prompt)
+	}
+
+	d.SetCursor(true)
+	d.SetBlink(true)
+	defer func() {
+		d.SetCursor(false)
+		d.SetBlink(false)
+	}()
+
+	for {
+		t.render(d, row, buf, pos)
+
+		button, err := nextPress(ctx, events)
+		if err != nil {
+			return "", err
+		}
+
+		switch button {
+		case charLCDRGBI2C.UpButton:
+			buf = cycle(buf, pos, charset, 1)
+		case charLCDRGBI2C.DownButton:
+			buf = cycle(buf, pos, charset, -1)
+		case charLCDRGBI2C.RightButton:
+			if pos < len(buf) && pos+1 < maxLength {
+				pos++
+			}
+		case charLCDRGBI2C.LeftButton:
+			if pos == 0 {
+				return "", ErrCancelled
+			}
+			pos--
+		case charLCDRGBI2C.SelectButton:
+			return string(buf), nil
+		}
+	}
+}
+
+// render draws the text scrolled so the cursor is visible and moves the
+// cursor to the character being edited
+func (t *TextInput) render(d charLCDRGBI2C.Display, row int, buf []byte, pos int) {
+	columns, _ := d.Size()
+
+	text := append([]byte(nil), buf...)
+	if t.Mask {
+		for i := range text {
+			if i != pos {
+				text[i] = '*'
+			}
+		}
+	}
+
+	start := 0
+	if pos >= columns {
+		start = pos - columns + 1
+	}
+	writeLine(d, row, string(text[start:]))
+	d.CursorPosition(pos-start, row)
+}
+
+// cycle steps the character at pos through the charset. The last character
+// has an extra empty step that removes it, the position after the last
+// character adds one.
+func cycle(buf []byte, pos int, charset string, direction int) []byte {
+	slots := len(charset)
+	if pos >= len(buf)-1 {
+		slots++
+	}
+
+	// The empty step is after the charset
+	index := len(charset)
+	if pos < len(buf) {
+		index = strings.IndexByte(charset, buf[pos])
+	}
+	index = ((index+direction)%slots + slots) % slots
+
+	switch {
+	case index == len(charset):
+		return buf[:pos]
+	case pos == len(buf):
+		return append(buf, charset[index])
+	default:
+		buf[pos] = charset[index]
+		return buf
+	}
+}
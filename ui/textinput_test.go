@@ -0,0 +1,121 @@
+package ui_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jyap808/charLCDRGBI2C"
+	"github.com/jyap808/charLCDRGBI2C/sim"
+	"github.com/jyap808/charLCDRGBI2C/ui"
+)
+
+func TestTextInput(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   ui.TextInput
+		buttons []string
+		want    string
+	}{
+		{"add a character", ui.TextInput{Charset: ui.CharsetDigits}, []string{up, up}, "1"},
+		{"add from the end of the charset", ui.TextInput{Charset: ui.CharsetDigits}, []string{down}, "9"},
+		{"change a character", ui.TextInput{Charset: ui.CharsetDigits, Value: "123"}, []string{right, right, down}, "122"},
+		{"cycle within the charset", ui.TextInput{Charset: ui.CharsetDigits, Value: "900"}, []string{up}, "000"},
+		{"delete the last character", ui.TextInput{Charset: ui.CharsetDigits, Value: "129"}, []string{right, right, up}, "12"},
+		{"delete by cycling down", ui.TextInput{Charset: ui.CharsetDigits, Value: "10"}, []string{right, down}, "1"},
+		{"append after the last character", ui.TextInput{Charset: ui.CharsetHostname, Value: "ab"}, []string{right, up, right, up}, "aca"},
+		{"move left", ui.TextInput{Charset: ui.CharsetDigits, Value: "11"}, []string{right, left, up}, "21"},
+		{"max length", ui.TextInput{Charset: ui.CharsetDigits, MaxLength: 2}, []string{up, right, up, right, up}, "01"},
+		{"value cut to max length", ui.TextInput{Value: "abcdef", MaxLength: 3}, nil, "abc"},
+		{"default charset", ui.TextInput{Value: "~~"}, []string{up}, " ~"},
+	}
+	for _, tt := range tests {
+		lcd, _ := sim.NewLCD(t, charLCDRGBI2C.Geometry16x2, sim.Wiring{})
+		got, err := tt.input.Run(context.Background(), lcd, taps(append(tt.buttons, select_)...))
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTextInputCancel(t *testing.T) {
+	lcd, _ := sim.NewLCD(t, charLCDRGBI2C.Geometry16x2, sim.Wiring{})
+	input := ui.TextInput{Value: "abc"}
+	if _, err := input.Run(context.Background(), lcd, taps(right, left, left)); !errors.Is(err, ui.ErrCancelled) {
+		t.Errorf("got %v, want %v", err, ui.ErrCancelled)
+	}
+}
+
+func TestTextInputDisplay(t *testing.T) {
+	tests := []struct {
+		name     string
+		geometry charLCDRGBI2C.Geometry
+		input    ui.TextInput
+		buttons  []string
+		want     []string
+		column   int // Cursor column
+	}{
+		{"prompt", charLCDRGBI2C.Geometry16x2, ui.TextInput{Prompt: "Name", Value: "bob"},
+			[]string{right}, []string{"Name", "bob"}, 1},
+		{"1-line display", charLCDRGBI2C.Geometry16x1, ui.TextInput{Prompt: "Name", Value: "bob"},
+			[]string{right, right}, []string{"bob"}, 2},
+		{"mask", charLCDRGBI2C.Geometry16x2, ui.TextInput{Value: "abc", Mask: true},
+			[]string{right}, []string{"", "*b*"}, 1},
+		{"scrolled", charLCDRGBI2C.Geometry16x2, ui.TextInput{Value: "abcdefghijklmnopqrst", MaxLength: 20},
+			repeat(right, 17), []string{"", "cdefghijklmnopqr"}, 15},
+	}
+	for _, tt := range tests {
+		lcd, dev := sim.NewLCD(t, tt.geometry, sim.Wiring{})
+		// Stop while editing, the channel closes after the last button
+		if _, err := tt.input.Run(context.Background(), lcd, taps(tt.buttons...)); !errors.Is(err, ui.ErrClosed) {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		for row, line := range dev.Codes() {
+			if got := strings.TrimRight(string(line), " "); got != tt.want[row] {
+				t.Errorf("%s: line %d shows %q, want %q", tt.name, row, got, tt.want[row])
+			}
+		}
+	}
+
+	// The cursor blinks on the character being edited while Run waits
+	for _, tt := range tests {
+		lcd, dev := sim.NewLCD(t, tt.geometry, sim.Wiring{})
+		events := make(chan charLCDRGBI2C.ButtonEvent)
+		done := make(chan error)
+		go func() {
+			_, err := tt.input.Run(context.Background(), lcd, events)
+			done <- err
+		}()
+		for _, button := range tt.buttons {
+			events <- charLCDRGBI2C.ButtonEvent{Button: button, Pressed: true}
+		}
+		// Sent once the previous press has been handled and drawn
+		events <- charLCDRGBI2C.ButtonEvent{}
+		column, row, blink, ok := dev.Cursor()
+		if !ok || !blink || column != tt.column || row != tt.geometry.Lines-1 {
+			t.Errorf("%s: cursor at %d,%d shown %v blink %v, want %d,%d", tt.name, column, row, ok, blink, tt.column, tt.geometry.Lines-1)
+		}
+		events <- charLCDRGBI2C.ButtonEvent{Button: select_, Pressed: true}
+		if err := <-done; err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+		}
+		if _, _, _, ok := dev.Cursor(); ok {
+			t.Errorf("%s: cursor still shown after Run", tt.name)
+		}
+	}
+}
+
+// repeat returns n presses of a button
+func repeat(button string, n int) []string {
+	buttons := make([]string, n)
+	for i := range buttons {
+		buttons[i] = button
+	}
+	return buttons
+}
@@ -0,0 +1,81 @@
+// Package ui provides menus, widgets and screens for character LCDs driven
+// by the on-board buttons.
+//
+// Widgets draw on a charLCDRGBI2C.Display and read button presses from a
+// channel of charLCDRGBI2C.ButtonEvent, usually from WatchButtons:
+//
+//	events := lcd.WatchButtons(ctx, 20*time.Millisecond)
+//	item, err := menu.Run(ctx, lcd, events)
+package ui
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/jyap808/charLCDRGBI2C"
+)
+
+var (
+	// ErrCancelled is returned when the user backs out with the Left button
+	ErrCancelled = errors.New("ui: cancelled")
+	// ErrClosed is returned when the button event channel is closed
+	ErrClosed = errors.New("ui: button events closed")
+)
+
+// Custom character locations used by the widgets. Widgets define them when
+// they start, dialogs put the previous patterns back only on displays that
+// can take a snapshot.
+const (
+	CursorGlyph byte = 0 // Selection marker
+	EditGlyph   byte = 1 // Value being edited
+)
+
+// Custom character patterns
+var (
+	cursorPattern = []byte{0x10, 0x18, 0x1C, 0x1E, 0x1C, 0x18, 0x10, 0x00}
+	editPattern   = []byte{0x04, 0x0E, 0x1F, 0x00, 0x1F, 0x0E, 0x04, 0x00}
+)
+
+// nextPress waits for the next button press and returns the button pin
+func nextPress(ctx context.Context, events <-chan charLCDRGBI2C.ButtonEvent) (string, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case event, ok := <-events:
+			if !ok {
+				return "", ErrClosed
+			}
+			if event.Pressed {
+				return event.Button, nil
+			}
+		}
+	}
+}
+
+// writeLine writes text at the start of a row, padded or cut to the width
+// of the display
+func writeLine(d charLCDRGBI2C.Display, row int, text string) {
+	columns, _ := d.Size()
+	d.CursorPosition(0, row)
+	d.Message(fit(lcdText(text), columns))
+}
+
+// lcdText maps characters to the HD44780 character ROM. Message writes the
+// low byte of each rune, so the degree sign becomes U+00DF to write 0xDF.
+func lcdText(text string) string {
+	return strings.ReplaceAll(text, "°", "\u00df")
+}
+
+// fit pads or cuts text to width characters
+func fit(text string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	runes := []rune(text)
+	if len(runes) > width {
+		return string(runes[:width])
+	}
+	return text + strings.Repeat(" ", width-len(runes))
+}
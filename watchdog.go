@@ -0,0 +1,190 @@
+package charLCDRGBI2C
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// BoardStatus is the state of the I/O expander seen by WatchBoard
+type BoardStatus int
+
+const (
+	BoardOK       BoardStatus = iota // Responding with the pins set up
+	BoardMissing                     // Not responding on the bus
+	BoardReset                       // Responding but lost its pin setup, e.g. in a power cycle
+	BoardRestored                    // Set up again and the display redrawn
+)
+
+// String returns the name of a status
+func (s BoardStatus) String() string {
+	switch s {
+	case BoardOK:
+		return "ok"
+	case BoardMissing:
+		return "missing"
+	case BoardReset:
+		return "reset"
+	case BoardRestored:
+		return "restored"
+	}
+	return "unknown"
+}
+
+// BoardEvent reports a change in the state of the I/O expander
+type BoardEvent struct {
+	Status BoardStatus
+	Err    error     // Why the board is missing
+	Time   time.Time // When the change was seen
+}
+
+// WatchBoard probes the I/O expander every interval, reading back the pin
+// directions, and sends an event whenever its status changes. A board that
+// comes back after it was missing or reset is set up again: the pins are
+// configured, the LCD initialized and the display redrawn with its custom
+// characters, LED color and backlight, then BoardRestored is sent and the
+// reconnect handler called.
+//
+// Drivers that can not read back pin directions, see DirectionReader, are
+// only checked for bus errors. The board is watched whether or not the
+// events are read, a caller that falls behind loses the oldest ones. The
+// channel is closed when ctx is done or the LCD is closed.
+func (lcd *CharLCDRGBI2C) WatchBoard(ctx context.Context, interval time.Duration) <-chan BoardEvent {
+	events := make(chan BoardEvent, 2)
+
+	started := lcd.background(func(done <-chan struct{}) {
+		defer close(events)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		status := BoardOK
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-done:
+				return
+			case now := <-ticker.C:
+				var changes []BoardEvent
+				status, changes = lcd.checkBoard(status, now)
+
+				for _, event := range changes {
+					if event.Status == BoardRestored && lcd.recovery.onReconnect != nil {
+						lcd.recovery.onReconnect(lcd.Health())
+					}
+					sendLatest(events, event)
+				}
+			}
+		}
+	})
+	if !started {
+		close(events)
+	}
+	return events
+}
+
+// sendLatest sends event, dropping the oldest event queued when events is
+// full. The caller must be the only sender.
+func sendLatest(events chan BoardEvent, event BoardEvent) {
+	for {
+		select {
+		case events <- event:
+			return
+		default:
+		}
+		select {
+		case <-events:
+		default:
+		}
+	}
+}
+
+// checkBoard probes the board and sets it up again when it came back,
+// returning the new status and the changes seen
+func (lcd *CharLCDRGBI2C) checkBoard(last BoardStatus, now time.Time) (BoardStatus, []BoardEvent) {
+	lcd.mu.Lock()
+	defer lcd.mu.Unlock()
+
+	status, err := lcd.probe()
+	switch {
+	case status == BoardMissing && last == BoardMissing:
+		return last, nil
+	case status == BoardMissing:
+		lcd.recovery.failed(err)
+		return status, []BoardEvent{{Status: status, Err: err, Time: now}}
+	case status == BoardOK && last != BoardMissing:
+		return status, nil
+	}
+
+	// Back after it was missing, or reset
+	var changes []BoardEvent
+	if status == BoardReset {
+		changes = append(changes, BoardEvent{Status: status, Time: now})
+	}
+	if err := lcd.restoreBoard(); err != nil {
+		lcd.recovery.failed(err)
+		return BoardMissing, append(changes, BoardEvent{Status: BoardMissing, Err: err, Time: now})
+	}
+	lcd.recovery.recovered()
+	return BoardOK, append(changes, BoardEvent{Status: BoardRestored, Time: now})
+}
+
+// probe compares the pin directions of the board with what setupPins and
+// the backlight set
+func (lcd *CharLCDRGBI2C) probe() (BoardStatus, error) {
+	want := make(map[string]bool)
+	for _, pin := range append(lcd.lcdPins(), lcd.ledPins()...) {
+		want[pin] = true
+	}
+	for _, pin := range lcd.buttons {
+		want[pin] = false
+	}
+	if lcd.backlightPin != "" {
+		want[lcd.backlightPin] = lcd.backlight
+	}
+
+	pins := make([]string, 0, len(want))
+	for pin := range want {
+		pins = append(pins, pin)
+	}
+	var outputs map[string]bool
+	err := errors.ErrUnsupported
+	if reader, ok := lcd.pins.(DirectionReader); ok {
+		outputs, err = reader.Outputs(pins...)
+	}
+	if errors.Is(err, errors.ErrUnsupported) {
+		// Only a failing read tells something is wrong
+		if _, err := lcd.pins.Read(lcd.lcdPins()...); err != nil {
+			return BoardMissing, err
+		}
+		return BoardOK, nil
+	}
+	if err != nil {
+		return BoardMissing, err
+	}
+
+	for pin, output := range want {
+		if outputs[pin] != output {
+			return BoardReset, nil
+		}
+	}
+	return BoardOK, nil
+}
+
+// restoreBoard sets the expander up from scratch and brings the display
+// back from the driver state
+func (lcd *CharLCDRGBI2C) restoreBoard() error {
+	if resetter, ok := lcd.pins.(Resetter); ok {
+		if err := resetter.Reset(); err != nil {
+			return err
+		}
+	}
+
+	r := &lcd.recovery
+	r.err = nil
+	lcd.reinitialize()
+	err := r.err
+	r.used, r.err = false, nil
+	return err
+}
@@ -0,0 +1,120 @@
+package charLCDRGBI2C_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jyap808/charLCDRGBI2C"
+	"github.com/jyap808/charLCDRGBI2C/sim"
+)
+
+// watched returns an LCD showing text, a color and a custom character, for
+// WatchBoard to restore
+func watched(t *testing.T) (*charLCDRGBI2C.CharLCDRGBI2C, *sim.Device) {
+	t.Helper()
+	lcd, dev := sim.NewLCD(t, charLCDRGBI2C.Geometry16x2, sim.Wiring{})
+	lcd.CreateChar(2, []byte{0x1f, 0x11, 0x11, 0x11, 0x11, 0x11, 0x1f, 0})
+	lcd.SetColor(100, 0, 0)
+	lcd.Message("Hello\n\x02")
+	return lcd, dev
+}
+
+// restored waits for the board to show what watched drew
+func restored(t *testing.T, dev *sim.Device) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		codes := dev.Codes()
+		red, green, blue := dev.LED()
+		if string(codes[0]) == "Hello           " && string(codes[1]) == "\x02               " &&
+			dev.Glyph(1, 2)[0] == 0x1f && red && !green && !blue && dev.Backlight() {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("board not restored, showing %q", codes)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// next returns the next board event
+func next(t *testing.T, events <-chan charLCDRGBI2C.BoardEvent) charLCDRGBI2C.BoardEvent {
+	t.Helper()
+	select {
+	case event, ok := <-events:
+		if !ok {
+			t.Fatal("events closed")
+		}
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a board event")
+	}
+	return charLCDRGBI2C.BoardEvent{}
+}
+
+func TestWatchBoard(t *testing.T) {
+	lcd, dev := watched(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	events := lcd.WatchBoard(ctx, 5*time.Millisecond)
+
+	dev.PowerCycle()
+	for _, want := range []charLCDRGBI2C.BoardStatus{charLCDRGBI2C.BoardReset, charLCDRGBI2C.BoardRestored} {
+		if event := next(t, events); event.Status != want {
+			t.Errorf("got %v after a power cycle, want %v", event.Status, want)
+		}
+	}
+	restored(t, dev)
+
+	dev.Unplug()
+	if event := next(t, events); event.Status != charLCDRGBI2C.BoardMissing || !errors.Is(event.Err, sim.ErrUnplugged) {
+		t.Errorf("got %v %v after unplugging, want %v", event.Status, event.Err, charLCDRGBI2C.BoardMissing)
+	}
+	dev.Plug()
+	for _, want := range []charLCDRGBI2C.BoardStatus{charLCDRGBI2C.BoardReset, charLCDRGBI2C.BoardRestored} {
+		if event := next(t, events); event.Status != want {
+			t.Errorf("got %v after plugging in, want %v", event.Status, want)
+		}
+	}
+	restored(t, dev)
+	if health := lcd.Health(); !health.OK || health.Recoveries != 2 {
+		t.Errorf("health %+v, want 2 recoveries", health)
+	}
+
+	cancel()
+	for range events {
+	}
+}
+
+// TestWatchBoardUnread checks the board is restored when nobody reads the
+// events, which keep the latest
+func TestWatchBoardUnread(t *testing.T) {
+	lcd, dev := watched(t)
+	events := lcd.WatchBoard(context.Background(), 5*time.Millisecond)
+
+	for i := range 3 {
+		dev.PowerCycle()
+		restored(t, dev)
+		if health := lcd.Health(); health.Recoveries != i+1 {
+			t.Fatalf("health %+v after %d power cycles", health, i+1)
+		}
+	}
+	if event := next(t, events); event.Status != charLCDRGBI2C.BoardReset {
+		t.Errorf("oldest event kept is %v, want %v", event.Status, charLCDRGBI2C.BoardReset)
+	}
+	if event := next(t, events); event.Status != charLCDRGBI2C.BoardRestored {
+		t.Errorf("latest event is %v, want %v", event.Status, charLCDRGBI2C.BoardRestored)
+	}
+}
+
+func TestWatchClosed(t *testing.T) {
+	lcd, _ := sim.NewLCD(t, charLCDRGBI2C.Geometry16x2, sim.Wiring{})
+	lcd.Close(charLCDRGBI2C.CloseOptions{})
+	if _, ok := <-lcd.WatchBoard(context.Background(), time.Millisecond); ok {
+		t.Error("WatchBoard sent an event on a closed LCD")
+	}
+	if _, ok := <-lcd.WatchButtons(context.Background(), time.Millisecond); ok {
+		t.Error("WatchButtons sent an event on a closed LCD")
+	}
+}